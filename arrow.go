@@ -0,0 +1,130 @@
+//go:build arrow
+
+package main
+
+import (
+	"errors"
+	"io"
+
+	"github.com/apache/arrow/go/v16/arrow"
+	"github.com/apache/arrow/go/v16/arrow/array"
+	"github.com/apache/arrow/go/v16/arrow/ipc"
+	"github.com/apache/arrow/go/v16/arrow/memory"
+)
+
+func init() {
+	taggedFormats["arrow-ipc"] = func(*options) outputFormat {
+		return outputFormat{filename: "pending_payment_tasks.arrow", generate: generateArrowIPC}
+	}
+}
+
+// arrowTimestamp is the type of created_at and updated_at: int64
+// milliseconds since the Unix epoch, which pandas and Polars read as
+// datetimes.
+var arrowTimestamp = &arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "UTC"}
+
+var arrowSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "id", Type: arrow.BinaryTypes.String},
+	{Name: "title", Type: arrow.BinaryTypes.String},
+	{Name: "url", Type: arrow.BinaryTypes.String},
+	{Name: "created_at", Type: arrowTimestamp},
+	{Name: "updated_at", Type: arrowTimestamp},
+	{Name: "due_date", Type: arrow.BinaryTypes.String},
+	{Name: "created_by", Type: arrow.BinaryTypes.String},
+	{Name: "assigned_to", Type: arrow.ListOf(arrow.BinaryTypes.String)},
+	{Name: "labels", Type: arrow.ListOf(arrow.BinaryTypes.String)},
+	{Name: "description", Type: arrow.BinaryTypes.String},
+	{Name: "recipient", Type: arrow.BinaryTypes.String},
+	{Name: "bounty_amount", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	{Name: "bounty_symbol", Type: arrow.BinaryTypes.String},
+	{Name: "org", Type: arrow.BinaryTypes.String},
+	{Name: "project_number", Type: arrow.PrimitiveTypes.Int64},
+	{Name: "status", Type: arrow.BinaryTypes.String},
+	{Name: "sprint", Type: arrow.BinaryTypes.String},
+	{Name: "content_type", Type: arrow.BinaryTypes.String},
+	{Name: "signature_valid", Type: arrow.FixedWidthTypes.Boolean},
+}, nil)
+
+// generateArrowIPC writes the items as a single record batch in an Arrow IPC
+// file. Bounty amounts that are missing or not numeric are written as null.
+func generateArrowIPC(items []ProjectItem, filename string) error {
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, arrowSchema)
+	defer builder.Release()
+
+	appendString := func(i int, s string) { builder.Field(i).(*array.StringBuilder).Append(s) }
+	appendTime := func(i int, t arrow.Timestamp) { builder.Field(i).(*array.TimestampBuilder).Append(t) }
+	appendList := func(i int, values []string) {
+		list := builder.Field(i).(*array.ListBuilder)
+		list.Append(true)
+		list.ValueBuilder().(*array.StringBuilder).AppendValues(values, nil)
+	}
+
+	for _, item := range items {
+		appendString(0, item.ID)
+		appendString(1, item.Title)
+		appendString(2, item.URL)
+		appendTime(3, arrow.Timestamp(item.CreatedAt.UnixMilli()))
+		appendTime(4, arrow.Timestamp(item.UpdatedAt.UnixMilli()))
+		appendString(5, item.DueDate)
+		appendString(6, item.CreatedBy)
+		appendList(7, item.AssignedTo)
+		appendList(8, item.Labels)
+		appendString(9, item.Description)
+		appendString(10, item.Recipient)
+		if amount, err := parseBountyAmount(item.BountyAmount); err == nil {
+			builder.Field(11).(*array.Float64Builder).Append(amount)
+		} else {
+			builder.Field(11).AppendNull()
+		}
+		appendString(12, item.BountySymbol)
+		appendString(13, item.Org)
+		builder.Field(14).(*array.Int64Builder).Append(int64(item.ProjectNumber))
+		appendString(15, item.Status)
+		appendString(16, item.Sprint)
+		appendString(17, item.ContentType)
+		builder.Field(18).(*array.BooleanBuilder).Append(item.SignatureValid)
+	}
+
+	record := builder.NewRecord()
+	defer record.Release()
+
+	file, err := createOutput(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Abort()
+
+	writer, err := ipc.NewFileWriter(&positionWriter{w: file}, ipc.WithSchema(arrowSchema))
+	if err != nil {
+		return err
+	}
+	if err := writer.Write(record); err != nil {
+		writer.Close()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// positionWriter satisfies the io.WriteSeeker ipc.NewFileWriter asks for
+// over a plain writer, such as the one --dry-run returns. The file writer
+// only seeks to learn its current offset, so that is all Seek supports.
+type positionWriter struct {
+	w   io.Writer
+	pos int64
+}
+
+func (p *positionWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.pos += int64(n)
+	return n, err
+}
+
+func (p *positionWriter) Seek(offset int64, whence int) (int64, error) {
+	if offset != 0 || whence != io.SeekCurrent {
+		return 0, errors.New("arrow-ipc: output is not seekable")
+	}
+	return p.pos, nil
+}
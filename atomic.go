@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// atomicFile writes an output file to filename+".tmp" and renames it over
+// filename on Close, so an interrupted run leaves either the previous file
+// or the complete new one, never a partial write. If a write failed, or the
+// file is aborted because its generator failed, the temporary file is
+// removed and the previous one kept.
+type atomicFile struct {
+	file     *os.File
+	filename string
+	// err is the first write error; it is returned by Close.
+	err    error
+	closed bool
+}
+
+func newAtomicFile(filename string) (*atomicFile, error) {
+	tmp, err := os.OpenFile(filepath.Clean(filename)+".tmp", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &atomicFile{file: tmp, filename: filename}, nil
+}
+
+func (f *atomicFile) Write(p []byte) (int, error) {
+	n, err := f.file.Write(p)
+	if err != nil && f.err == nil {
+		f.err = err
+	}
+	return n, err
+}
+
+func (f *atomicFile) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+// Close syncs the temporary file and moves it into place. Calling it again
+// does nothing.
+func (f *atomicFile) Close() error {
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	tmp := f.file.Name()
+	err := f.err
+	if syncErr := f.file.Sync(); err == nil {
+		err = syncErr
+	}
+	if closeErr := f.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, f.filename)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
+}
+
+// Abort removes the temporary file unless Close was called first.
+func (f *atomicFile) Abort() {
+	if f.closed {
+		return
+	}
+	f.closed = true
+	f.file.Close()
+	os.Remove(f.file.Name())
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAtomicFileAbortKeepsPreviousFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "out.txt")
+	if err := os.WriteFile(filename, []byte("previous"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	generate := func() error {
+		file, err := createOutput(filename)
+		if err != nil {
+			return err
+		}
+		defer file.Abort()
+		if _, err := file.Write([]byte("partial")); err != nil {
+			return err
+		}
+		return errors.New("generator failed")
+	}
+	if err := generate(); err == nil {
+		t.Fatal("generate succeeded, want an error")
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil || string(data) != "previous" {
+		t.Errorf("file holds %q, %v, want the previous contents", data, err)
+	}
+	if _, err := os.Stat(filename + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+}
+
+func TestAtomicFileCloseReportsRenameError(t *testing.T) {
+	// A directory in the way makes the rename fail.
+	filename := filepath.Join(t.TempDir(), "out.txt")
+	if err := os.MkdirAll(filepath.Join(filename, "child"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file, err := createOutput(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Abort()
+	if _, err := file.Write([]byte("new")); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err == nil {
+		t.Error("Close succeeded, want the rename error")
+	}
+}
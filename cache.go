@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// GraphQLClient runs GitHub GraphQL queries. It is satisfied by
+// *githubv4.Client and by cachingClient.
+type GraphQLClient interface {
+	Query(ctx context.Context, q any, variables map[string]any) error
+}
+
+// cachingClient answers queries from JSON files in dir that are younger than
+// ttl and stores every response it fetches from next there, so repeated runs
+// during development do not hit the GitHub API each time.
+type cachingClient struct {
+	next GraphQLClient
+	dir  string
+	ttl  time.Duration
+}
+
+func newCachingClient(next GraphQLClient, dir string, ttl time.Duration) *cachingClient {
+	return &cachingClient{next: next, dir: dir, ttl: ttl}
+}
+
+// Query fills q from the cache when possible. A cache file that cannot be
+// read or written is ignored with a warning; the query then goes to GitHub
+// as usual.
+func (c *cachingClient) Query(ctx context.Context, q any, variables map[string]any) error {
+	path, err := c.path(q, variables)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) < c.ttl {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			err = json.Unmarshal(data, q)
+		}
+		if err == nil {
+			return nil
+		}
+		slog.Warn("Ignoring cached response", "file", path, "err", err)
+	}
+
+	if err := c.next.Query(ctx, q, variables); err != nil {
+		return err
+	}
+	if err := c.store(path, q); err != nil {
+		slog.Warn("Could not cache GitHub response", "err", err)
+	}
+	return nil
+}
+
+// path returns the cache file of a query: the SHA-256 of the query's Go
+// type, which githubv4 turns into the query text, and its variables.
+func (c *cachingClient) path(q any, variables map[string]any) (string, error) {
+	vars, err := json.Marshal(variables)
+	if err != nil {
+		return "", fmt.Errorf("serializing query variables: %w", err)
+	}
+	sum := sha256.Sum256(fmt.Appendf(nil, "%T\n%s", q, vars))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json"), nil
+}
+
+// store writes the response in q to path. The file is renamed into place so
+// a concurrent or interrupted run never reads a partial response.
+func (c *cachingClient) store(path string, q any) error {
+	data, err := json.Marshal(q)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, ".response-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// Periods accepted by --cap-period.
+const (
+	capPeriodRun   = "run"
+	capPeriodMonth = "month"
+	capPeriodYear  = "year"
+)
+
+// cappedRecipient is a recipient whose bounty total exceeds --recipient-cap.
+type cappedRecipient struct {
+	recipient string
+	total     float64
+	overage   float64
+}
+
+// cappedRecipients returns the recipients whose summed bounty exceeds limit,
+// sorted by recipient. With the month or year period only items created in
+// the calendar month or year of now count towards the total; with run every
+// exported item does.
+func cappedRecipients(items []ProjectItem, limit float64, period string, now time.Time) []cappedRecipient {
+	var start time.Time
+	switch period {
+	case capPeriodMonth:
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	case capPeriodYear:
+		start = time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+	}
+	var counted []ProjectItem
+	for _, item := range items {
+		if start.IsZero() || !item.CreatedAt.Before(start) {
+			counted = append(counted, item)
+		}
+	}
+
+	var capped []cappedRecipient
+	for recipient, total := range bountyByRecipient(counted) {
+		if total > limit {
+			capped = append(capped, cappedRecipient{recipient: recipient, total: total, overage: total - limit})
+		}
+	}
+	sort.Slice(capped, func(i, j int) bool { return capped[i].recipient < capped[j].recipient })
+	return capped
+}
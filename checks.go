@@ -0,0 +1,396 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A finding is a single problem reported by one of the data-quality checks.
+// Findings are warnings unless isError is set, which fails the run even
+// without --strict.
+type finding struct {
+	check   string
+	message string
+	isError bool
+}
+
+// runChecks runs the data-quality checks enabled in opts against items.
+func runChecks(ctx context.Context, client GraphQLClient, httpClient *http.Client, items []ProjectItem, opts *options) []finding {
+	var findings []finding
+	if opts.maxItemAgeDays > 0 {
+		findings = append(findings, checkItemAge(items, opts.maxItemAgeDays, time.Now())...)
+	}
+	if opts.recentActivityDays > 0 {
+		findings = append(findings, checkRecentActivity(items, opts.recentActivityDays, time.Now())...)
+	}
+	if opts.checkTotal > 0 {
+		findings = append(findings, checkTotal(items, opts.checkTotal, opts.checkTotalTolerance, opts.bountySymbol)...)
+	}
+	if opts.checkLabelRequired {
+		findings = append(findings, checkLabelRequired(items)...)
+	}
+	if opts.checkSelfAssignment {
+		findings = append(findings, checkSelfAssignment(items)...)
+	}
+	if len(opts.exclusiveLabels) > 0 {
+		findings = append(findings, checkLabelsExclusive(items, opts.exclusiveLabels)...)
+	}
+	if opts.labelPalette != nil {
+		findings = append(findings, checkLabelColor(items, opts.labelPalette)...)
+	}
+	if opts.minDescriptionLength > 0 {
+		findings = append(findings, checkDescriptionLength(items, opts.minDescriptionLength)...)
+	}
+	if opts.gitleaks != nil {
+		findings = append(findings, checkGitleaks(items, opts.gitleaks)...)
+	}
+	if opts.checkBountyInteger {
+		findings = append(findings, checkBountyInteger(items)...)
+	}
+	if opts.checkLinkedPR {
+		findings = append(findings, checkLinkedPR(items)...)
+	}
+	if opts.checkIssuer {
+		findings = append(findings, checkIssuer(items, opts.repoFilters)...)
+	}
+	if opts.checkIBAN {
+		findings = append(findings, checkIBAN(items)...)
+	}
+	if opts.checkNoDuplicateURLs {
+		findings = append(findings, checkDuplicateURLs(items)...)
+	}
+	if opts.checkURLReachable {
+		findings = append(findings, checkURLReachable(ctx, httpClient, items, opts.concurrency)...)
+	}
+	if opts.checkOrgMembership {
+		findings = append(findings, checkOrgMembership(ctx, client, items)...)
+	}
+	if opts.checkWalletBalance != "" {
+		total := strconv.FormatFloat(totalBounty(items), 'f', -1, 64)
+		if err := checkWalletBalance(ctx, opts.ethereumRPC, opts.tokenAddress, opts.checkWalletBalance, total); err != nil {
+			findings = append(findings, finding{check: "wallet-balance", message: err.Error()})
+		}
+	}
+	return findings
+}
+
+// reportFindings logs each finding, grouped by check in the order the
+// checks ran, followed by a line counting the findings of every check.
+// Findings are warnings unless strict is set, in which case they are logged
+// as errors and make the run fail before any output is written. Findings
+// marked isError always do.
+func reportFindings(findings []finding, strict bool) error {
+	failed := 0
+	var checks []string
+	counts := make(map[string]int)
+	for _, f := range findings {
+		if counts[f.check] == 0 {
+			checks = append(checks, f.check)
+		}
+		counts[f.check]++
+	}
+	for _, check := range checks {
+		for _, f := range findings {
+			if f.check != check {
+				continue
+			}
+			if strict || f.isError {
+				failed++
+				slog.Error(f.message, "check", f.check)
+			} else {
+				slog.Warn(f.message, "check", f.check)
+			}
+		}
+	}
+	if len(checks) > 1 {
+		level := slog.LevelWarn
+		if failed > 0 {
+			level = slog.LevelError
+		}
+		summary := make([]any, 0, len(checks)+1)
+		summary = append(summary, "finding_count", len(findings))
+		for _, check := range checks {
+			summary = append(summary, slog.Int(check, counts[check]))
+		}
+		slog.Log(context.Background(), level, "Check findings by check", summary...)
+	}
+	if failed > 0 && strict {
+		return fmt.Errorf("%d check finding(s) with --strict", failed)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d check error(s)", failed)
+	}
+	return nil
+}
+
+// enableAllChecks turns on every check that needs no value of its own, for
+// --check-all. Checks configured with a threshold or address, such as
+// --check-total or --check-wallet-balance, still have to be given
+// explicitly, and so does --check-iban, which only makes sense for projects
+// that pay bank accounts rather than wallet addresses and would otherwise
+// flag every recipient. --check-github-project-status is left off with
+// --project-id, which skips the project lookup it relies on, and
+// --check-linked-pr with --from-cache, which does not store linked pull
+// requests. --check-label-color needs --label-palette, so it is never
+// enabled here either; it is rejected with --from-cache, which does not
+// store label colors.
+func enableAllChecks(opts *options) {
+	opts.checkProjectStatus = opts.projectID == ""
+	opts.checkLabelRequired = true
+	opts.checkSelfAssignment = true
+	opts.checkBountyInteger = true
+	opts.checkLinkedPR = !opts.fromCache
+	opts.checkIssuer = true
+	opts.checkNoDuplicateURLs = true
+	opts.checkURLReachable = true
+	opts.checkOrgMembership = true
+}
+
+// checkItemAge reports items created more than maxDays days before now. Old
+// items still awaiting payment point at a stale payment queue.
+func checkItemAge(items []ProjectItem, maxDays int, now time.Time) []finding {
+	var findings []finding
+	for _, item := range items {
+		age := int(now.Sub(item.CreatedAt).Hours() / 24)
+		if age > maxDays {
+			findings = append(findings, finding{
+				check:   "item-age",
+				message: fmt.Sprintf("%s is %d days old (limit %d)", item.URL, age, maxDays),
+			})
+		}
+	}
+	return findings
+}
+
+// checkRecentActivity reports when no item has been updated in the last
+// days days. A project where nothing moves for that long is usually not the
+// project the tool was meant to query.
+func checkRecentActivity(items []ProjectItem, days int, now time.Time) []finding {
+	if len(items) == 0 {
+		return nil
+	}
+	var latest time.Time
+	for _, item := range items {
+		if item.UpdatedAt.After(latest) {
+			latest = item.UpdatedAt
+		}
+	}
+	idle := int(now.Sub(latest).Hours() / 24)
+	if idle <= days {
+		return nil
+	}
+	return []finding{{
+		check:   "recent-activity",
+		message: fmt.Sprintf("no item has been updated in %d days (last update %s, limit %d)", idle, latest.Format(time.RFC3339), days),
+	}}
+}
+
+// checkTotal reports when the total bounty of items differs from expected by
+// more than tolerancePercent percent of expected. A zero tolerance requires
+// an exact match. Amounts are reported in symbol, the --bounty-symbol.
+func checkTotal(items []ProjectItem, expected, tolerancePercent float64, symbol string) []finding {
+	total := totalBounty(items)
+	allowed := expected * tolerancePercent / 100
+	if math.Abs(total-expected) <= allowed {
+		return nil
+	}
+	return []finding{{
+		check: "total",
+		message: fmt.Sprintf("total bounty is %.2f %s, expected %.2f %s (allowed range %.2f–%.2f)",
+			total, symbol, expected, symbol, expected-allowed, expected+allowed),
+	}}
+}
+
+// checkBountyInteger reports items whose bounty has a fractional part, for
+// tokens whose contracts only transfer whole amounts.
+func checkBountyInteger(items []ProjectItem) []finding {
+	var findings []finding
+	for _, item := range items {
+		amount, err := parseBountyAmount(item.BountyAmount)
+		if err != nil || amount == math.Trunc(amount) {
+			continue
+		}
+		findings = append(findings, finding{
+			check:   "bounty-integer",
+			message: fmt.Sprintf("%s has a fractional bounty of %s %s", item.URL, item.BountyAmount, item.BountySymbol),
+		})
+	}
+	return findings
+}
+
+// checkLinkedPR reports issues that no pull request cross-references. A
+// linked pull request is the proof of work a bounty is paid for. Pull
+// request items are their own proof and are skipped, and so are draft
+// issues, which cannot be referenced.
+func checkLinkedPR(items []ProjectItem) []finding {
+	var findings []finding
+	for _, item := range items {
+		if item.ContentType == contentTypeIssue && item.URL != "" && len(item.LinkedPullRequests) == 0 {
+			findings = append(findings, finding{
+				check:   "linked-pr",
+				message: fmt.Sprintf("%s has no linked pull request", item.URL),
+			})
+		}
+	}
+	return findings
+}
+
+// checkIssuer reports items whose issue does not belong to a repository of
+// the organization the project was read from or, when repos lists
+// --repo-filter OWNER/REPO entries, to one of those repositories. Projects
+// can link issues from anywhere, so a corrupted or shared project may
+// contain unrelated ones. Draft items have no URL and are skipped.
+func checkIssuer(items []ProjectItem, repos []string) []finding {
+	var findings []finding
+	for _, item := range items {
+		if item.URL == "" {
+			continue
+		}
+		owner, repo := "", ""
+		if u, err := url.Parse(item.URL); err == nil {
+			parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 3)
+			owner = parts[0]
+			if len(parts) > 1 {
+				repo = parts[1]
+			}
+		}
+		inRepos := slices.ContainsFunc(repos, func(r string) bool {
+			return strings.EqualFold(strings.Trim(r, "/"), owner+"/"+repo)
+		})
+		var message string
+		switch {
+		case len(repos) > 0 && !inRepos:
+			message = fmt.Sprintf("%s does not belong to any of the repositories %s", item.URL, strings.Join(repos, ", "))
+		case len(repos) == 0 && !strings.EqualFold(owner, item.Org):
+			message = fmt.Sprintf("%s does not belong to a repository of %s", item.URL, item.Org)
+		default:
+			continue
+		}
+		findings = append(findings, finding{check: "issuer", message: message})
+	}
+	return findings
+}
+
+// checkIBAN reports items whose recipient is not a valid IBAN, with the
+// check digits the IBAN should have when only they are wrong.
+func checkIBAN(items []ProjectItem) []finding {
+	var findings []finding
+	for _, item := range items {
+		iban := normalizeIBAN(item.Recipient)
+		expected, ok := ibanCheckDigits(iban)
+		var message string
+		switch {
+		case !ok:
+			message = fmt.Sprintf("%s: recipient %q is not an IBAN", item.URL, item.Recipient)
+		case expected != iban[2:4]:
+			message = fmt.Sprintf("%s: recipient %q has check digits %s, expected %s", item.URL, item.Recipient, iban[2:4], expected)
+		default:
+			continue
+		}
+		findings = append(findings, finding{check: "iban", message: message})
+	}
+	return findings
+}
+
+// checkDuplicateURLs reports issues linked to the project more than once
+// under different item IDs, which would otherwise be paid twice.
+func checkDuplicateURLs(items []ProjectItem) []finding {
+	idsByURL := make(map[string][]string)
+	var urls []string
+	for _, item := range items {
+		if item.URL == "" {
+			continue
+		}
+		if _, seen := idsByURL[item.URL]; !seen {
+			urls = append(urls, item.URL)
+		}
+		idsByURL[item.URL] = append(idsByURL[item.URL], item.ID)
+	}
+
+	var findings []finding
+	for _, url := range urls {
+		if ids := idsByURL[url]; len(ids) > 1 {
+			findings = append(findings, finding{
+				check:   "duplicate-urls",
+				message: fmt.Sprintf("%s appears %d times (items %s)", url, len(ids), strings.Join(ids, ", ")),
+			})
+		}
+	}
+	return findings
+}
+
+// checkLabelRequired reports items without any label, which are hard to
+// categorize for accounting.
+func checkLabelRequired(items []ProjectItem) []finding {
+	var findings []finding
+	for _, item := range items {
+		if len(item.Labels) == 0 {
+			findings = append(findings, finding{check: "label-required", message: item.URL + " has no labels"})
+		}
+	}
+	return findings
+}
+
+// checkSelfAssignment reports items whose author is also their only
+// assignee. Someone who opened an issue and took it on alone should have the
+// work reviewed by a second person before the bounty is paid.
+func checkSelfAssignment(items []ProjectItem) []finding {
+	var findings []finding
+	for _, item := range items {
+		if item.CreatedBy != "" && len(item.AssignedTo) == 1 && strings.EqualFold(item.AssignedTo[0], item.CreatedBy) {
+			findings = append(findings, finding{
+				check:   "no-self-assignment",
+				message: fmt.Sprintf("%s was created by its only assignee %s; get a second-party review before paying", item.URL, item.CreatedBy),
+			})
+		}
+	}
+	return findings
+}
+
+// checkLabelsExclusive reports items with more than one label starting with
+// the same prefix, such as both type:bug and type:feature, as errors: each
+// prefix names a group of mutually exclusive labels.
+func checkLabelsExclusive(items []ProjectItem, prefixes []string) []finding {
+	var findings []finding
+	for _, item := range items {
+		for _, prefix := range prefixes {
+			var group []string
+			for _, label := range item.Labels {
+				if strings.HasPrefix(label, prefix) {
+					group = append(group, label)
+				}
+			}
+			if len(group) > 1 {
+				findings = append(findings, finding{
+					check:   "labels-exclusive",
+					message: fmt.Sprintf("%s has %d %s* labels, at most one is allowed: %s", item.URL, len(group), prefix, strings.Join(group, ", ")),
+					isError: true,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// checkDescriptionLength reports items whose description is shorter than
+// minLength characters, a sign of incomplete issue documentation.
+func checkDescriptionLength(items []ProjectItem, minLength int) []finding {
+	var findings []finding
+	for _, item := range items {
+		if n := len(strings.TrimSpace(item.Description)); n < minLength {
+			findings = append(findings, finding{
+				check:   "description-length",
+				message: fmt.Sprintf("%s has a %d character description (minimum %d)", item.URL, n, minLength),
+			})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func TestEnableAllChecksLeavesOutIBAN(t *testing.T) {
+	opts := &options{}
+	enableAllChecks(opts)
+	if opts.checkIBAN {
+		t.Error("--check-all enabled --check-iban, which flags every wallet address recipient")
+	}
+	if !opts.checkIssuer || !opts.checkLabelRequired {
+		t.Error("--check-all did not enable the value-less checks")
+	}
+}
+
+func TestCheckIssuer(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		repos   []string
+		flagged bool
+	}{
+		{name: "organization repository", url: "https://github.com/NautilusOSS/tools/issues/1"},
+		{name: "other organization", url: "https://github.com/Elsewhere/tools/issues/1", flagged: true},
+		{name: "draft item", url: ""},
+		{name: "filtered repository", url: "https://github.com/NautilusOSS/tools/issues/1", repos: []string{"nautilusoss/tools"}},
+		{name: "organization repository outside the filter", url: "https://github.com/NautilusOSS/other/issues/1", repos: []string{"NautilusOSS/tools"}, flagged: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := checkIssuer([]ProjectItem{{URL: tt.url, Org: "NautilusOSS"}}, tt.repos)
+			if got := len(findings) > 0; got != tt.flagged {
+				t.Errorf("flagged = %v, want %v (findings %v)", got, tt.flagged, findings)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFile is read from the current directory when --config is not
+// given. It is optional; a missing default file is not an error.
+const defaultConfigFile = "buidl-tools.yaml"
+
+// fileConfig is the YAML configuration file. Every setting can also be given
+// on the command line, which takes precedence, except github_token, which
+// is overridden by the GITHUB_TOKEN environment variable.
+type fileConfig struct {
+	GitHubToken   string   `yaml:"github_token"`
+	Org           string   `yaml:"org"`
+	ProjectNumber int      `yaml:"project_number"`
+	StatusFilter  []string `yaml:"status_filter"`
+	OutputDir     string   `yaml:"output_dir"`
+	OutputFormat  string   `yaml:"output_format"`
+}
+
+// loadConfig reads the configuration file at path. When required is false a
+// missing file yields an empty configuration. Unknown keys are rejected so
+// that typos do not go unnoticed.
+func loadConfig(path string, required bool) (fileConfig, error) {
+	var cfg fileConfig
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
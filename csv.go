@@ -0,0 +1,381 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/message"
+	"golang.org/x/text/transform"
+)
+
+// --csv-mode values.
+const (
+	csvOverwrite = "overwrite"
+	csvAppend    = "append"
+)
+
+// csvColumn describes one column of the CSV export. Type is the annotation
+// written by the csv-typed format and follows the CSV Schema / csvkit
+// vocabulary (string, url, datetime, number, boolean, list[string]).
+type csvColumn struct {
+	Name  string
+	Type  string
+	Value func(item ProjectItem) string
+}
+
+var csvColumns = []csvColumn{
+	{"ID", "string", func(item ProjectItem) string { return item.ID }},
+	{"Title", "string", func(item ProjectItem) string { return item.Title }},
+	{"URL", "url", func(item ProjectItem) string { return item.URL }},
+	{"Created At", "datetime", func(item ProjectItem) string { return item.CreatedAt.Format(time.RFC3339) }},
+	{"Updated At", "datetime", func(item ProjectItem) string { return item.UpdatedAt.Format(time.RFC3339) }},
+	{"Due Date", "datetime", func(item ProjectItem) string { return item.DueDate }},
+	{"Assigned To", "list[string]", func(item ProjectItem) string { return strings.Join(item.AssignedTo, ";") }},
+	{"Labels", "list[string]", func(item ProjectItem) string { return strings.Join(item.Labels, ";") }},
+	{"Description", "string", func(item ProjectItem) string { return item.Description }},
+	{"Recipient", "string", func(item ProjectItem) string { return item.Recipient }},
+	{"Bounty Amount", "number", func(item ProjectItem) string { return item.BountyAmount }},
+	{"Bounty Symbol", "string", func(item ProjectItem) string { return item.BountySymbol }},
+	{"Org", "string", func(item ProjectItem) string { return item.Org }},
+	{"Project Number", "number", func(item ProjectItem) string { return strconv.Itoa(item.ProjectNumber) }},
+	{"Content Type", "string", func(item ProjectItem) string { return item.ContentType }},
+}
+
+// csvOptions controls the layout shared by the CSV-based formats.
+type csvOptions struct {
+	// typed adds a second header row holding the type of each column, for
+	// tools that cannot infer types reliably.
+	typed bool
+	// expandMultiValue writes one row per assignee, with an Assignee and an
+	// assignee_index column identifying which assignee the row represents.
+	expandMultiValue bool
+	// utf16le encodes the file as UTF-16LE with a byte order mark and CRLF
+	// line endings, as expected by Windows finance tools such as SAP.
+	utf16le bool
+	// semicolon separates fields with ';' and writes numbers with a decimal
+	// comma, for spreadsheets in European locales.
+	semicolon bool
+	// sprint adds the Sprint column read from --sprint-field.
+	sprint bool
+	// signatures adds the Signature Valid column set by --verify-signatures.
+	signatures bool
+	// hash appends a row_hash column holding the SHA-256 of the row's other
+	// values, so later edits to the file can be detected.
+	hash bool
+	// normalizeDecimals, when set, writes bounty amounts as integers in the
+	// smallest unit of each token, keyed by symbol.
+	normalizeDecimals map[string]int
+	// expandAssignees replaces the semicolon-joined Assigned To column with
+	// numbered Assignee N columns, as many as the item with the most
+	// assignees needs.
+	expandAssignees bool
+	// wide adds numbered AssignedTo_N and Labels_N columns holding one value
+	// each, as many as the largest list across all items needs.
+	wide bool
+	// money, when set, writes bounty amounts with the symbol and the
+	// thousand separators and decimal mark of its locale, e.g. 1,500 BUIDL.
+	money *message.Printer
+	// base64 encodes the user-controlled columns listed in base64Columns,
+	// against formula injection.
+	base64 bool
+	// annotated adds a second header row describing each column's source
+	// and type, starting with '#' so it reads as a comment.
+	annotated bool
+	// appendRows adds rows to an existing file instead of replacing it,
+	// leaving out the header and items whose ID the file already holds.
+	appendRows bool
+}
+
+// Columns outside csvColumns. Sprint and Signature Valid are added to the
+// CSV by their flags; Created By is only available to --fields, so the
+// columns of existing CSV ledgers stay as they are.
+var (
+	sprintColumn    = csvColumn{"Sprint", "string", func(item ProjectItem) string { return item.Sprint }}
+	signatureColumn = csvColumn{"Signature Valid", "boolean", func(item ProjectItem) string { return strconv.FormatBool(item.SignatureValid) }}
+	createdByColumn = csvColumn{"Created By", "string", func(item ProjectItem) string { return item.CreatedBy }}
+)
+
+func generateCSV(items []ProjectItem, filename string, opts csvOptions) error {
+	// Amounts are checked before the file is created so an invalid one
+	// leaves the previous export in place.
+	if opts.normalizeDecimals != nil {
+		for _, item := range items {
+			if item.BountyAmount == "" {
+				continue
+			}
+			if _, err := normalizeAmount(item.BountyAmount, item.BountySymbol, opts.normalizeDecimals); err != nil {
+				return fmt.Errorf("%s: %w", item.URL, err)
+			}
+		}
+	}
+
+	var existingHeader []string
+	if opts.appendRows {
+		header, ids, err := readCSVIDs(filename)
+		if err != nil {
+			return err
+		}
+		existingHeader = header
+		items = slices.DeleteFunc(slices.Clone(items), func(item ProjectItem) bool { return ids[item.ID] })
+	}
+
+	open := createOutput
+	if existingHeader != nil {
+		open = appendOutput
+	}
+	file, err := open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Abort()
+
+	var out io.Writer = file
+	var encoder io.WriteCloser
+	if opts.utf16le {
+		encoder = transform.NewWriter(file, unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder())
+		out = encoder
+	}
+
+	writer := csv.NewWriter(out)
+	writer.UseCRLF = opts.utf16le
+	if opts.semicolon {
+		writer.Comma = ';'
+	}
+
+	columns := csvColumns
+	if opts.normalizeDecimals != nil {
+		columns = slices.Clone(columns)
+		for i, col := range columns {
+			if col.Name == "Bounty Amount" {
+				columns[i].Value = func(item ProjectItem) string {
+					amount, _ := normalizeAmount(item.BountyAmount, item.BountySymbol, opts.normalizeDecimals)
+					return amount
+				}
+			}
+		}
+	}
+	if opts.money != nil {
+		columns = slices.Clone(columns)
+		for i, col := range columns {
+			if col.Name == "Bounty Amount" {
+				columns[i] = csvColumn{col.Name, "string", func(item ProjectItem) string {
+					return formatMoney(opts.money, item.BountyAmount, item.BountySymbol)
+				}}
+			}
+		}
+	}
+	if opts.base64 {
+		columns = encodeBase64Columns(columns)
+	}
+	if opts.expandAssignees {
+		maxAssignees := 0
+		for _, item := range items {
+			maxAssignees = max(maxAssignees, len(item.AssignedTo))
+		}
+		i := slices.IndexFunc(columns, func(col csvColumn) bool { return col.Name == "Assigned To" })
+		columns = slices.Concat(columns[:i], listColumns("Assignee %d", maxAssignees, func(item ProjectItem) []string { return item.AssignedTo }), columns[i+1:])
+	}
+	if opts.sprint {
+		columns = append(columns[:len(columns):len(columns)], sprintColumn)
+	}
+	if opts.signatures {
+		columns = append(columns[:len(columns):len(columns)], signatureColumn)
+	}
+	if opts.wide {
+		maxAssignees, maxLabels := 0, 0
+		for _, item := range items {
+			maxAssignees = max(maxAssignees, len(item.AssignedTo))
+			maxLabels = max(maxLabels, len(item.Labels))
+		}
+		columns = append(columns[:len(columns):len(columns)], listColumns("AssignedTo_%d", maxAssignees, func(item ProjectItem) []string { return item.AssignedTo })...)
+		columns = append(columns, listColumns("Labels_%d", maxLabels, func(item ProjectItem) []string { return item.Labels })...)
+	}
+
+	// Write header
+	header := make([]string, len(columns))
+	types := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.Name
+		types[i] = col.Type
+	}
+	if opts.expandMultiValue {
+		header = append(header, "Assignee", "assignee_index")
+		types = append(types, "string", "number")
+	}
+	if opts.hash {
+		header = append(header, rowHashColumn)
+		types = append(types, "string")
+	}
+	if existingHeader != nil {
+		if !slices.Equal(existingHeader, header) {
+			return fmt.Errorf("cannot append to %s: its columns differ from this export's (%s); use --csv-mode overwrite", filename, strings.Join(existingHeader, ", "))
+		}
+	} else if err := writer.Write(header); err != nil {
+		return err
+	}
+	if opts.typed {
+		if err := writer.Write(types); err != nil {
+			return err
+		}
+	}
+	if opts.annotated {
+		if err := writer.Write(csvAnnotations(header, types)); err != nil {
+			return err
+		}
+	}
+
+	// Write data
+	writeRow := func(row []string) error {
+		if opts.hash {
+			row = append(row[:len(row):len(row)], rowHash(row))
+		}
+		return writer.Write(row)
+	}
+	for _, item := range items {
+		row := make([]string, len(columns))
+		for i, col := range columns {
+			row[i] = col.Value(item)
+			if opts.semicolon && col.Type == "number" {
+				row[i] = strings.Replace(row[i], ".", ",", 1)
+			}
+		}
+		if !opts.expandMultiValue {
+			if err := writeRow(row); err != nil {
+				return err
+			}
+			continue
+		}
+		// Items without assignees still get a single row so they are not
+		// dropped from the export.
+		if len(item.AssignedTo) == 0 {
+			if err := writeRow(append(row, "", "")); err != nil {
+				return err
+			}
+		}
+		for i, assignee := range item.AssignedTo {
+			expanded := append(row[:len(row):len(row)], assignee, strconv.Itoa(i+1))
+			if err := writeRow(expanded); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("writing %s: %w", filename, err)
+	}
+	if encoder != nil {
+		if err := encoder.Close(); err != nil {
+			return err
+		}
+	}
+	return file.Close()
+}
+
+// readCSVIDs returns the header of the CSV file filename and the values of
+// its ID column. A missing or empty file yields a nil header.
+func readCSVIDs(filename string) ([]string, map[string]bool, error) {
+	file, err := os.Open(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	idColumn := slices.Index(header, "ID")
+	if idColumn < 0 {
+		return nil, nil, fmt.Errorf("cannot append to %s: it has no ID column", filename)
+	}
+	ids := make(map[string]bool)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return header, ids, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading %s: %w", filename, err)
+		}
+		ids[record[idColumn]] = true
+	}
+}
+
+// listColumns returns n numbered columns, named by nameFormat, that each hold
+// one entry of the list returned by values. Items with shorter lists get
+// empty cells.
+func listColumns(nameFormat string, n int, values func(item ProjectItem) []string) []csvColumn {
+	columns := make([]csvColumn, n)
+	for i := range columns {
+		columns[i] = csvColumn{fmt.Sprintf(nameFormat, i+1), "string", func(item ProjectItem) string {
+			if list := values(item); i < len(list) {
+				return list[i]
+			}
+			return ""
+		}}
+	}
+	return columns
+}
+
+// generateCSVPivot writes a pivot table with one column per recipient and a
+// single Total row holding each recipient's summed bounty. Recipients
+// without a numeric bounty show 0, so every column has a value.
+func generateCSVPivot(items []ProjectItem, w io.Writer) error {
+	totals := bountyByRecipient(items)
+	recipients := sortedKeys(totals)
+
+	header := append([]string{"Recipient"}, recipients...)
+	row := []string{"Total"}
+	for _, recipient := range recipients {
+		row = append(row, strconv.FormatFloat(totals[recipient], 'f', -1, 64))
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	if err := writer.Write(row); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// selectColumns returns the columns named in names, matched
+// case-insensitively against the CSV headers, in the given order. An empty
+// names selects every column.
+func selectColumns(names []string) ([]csvColumn, error) {
+	if len(names) == 0 {
+		return csvColumns, nil
+	}
+	available := append(csvColumns[:len(csvColumns):len(csvColumns)], sprintColumn, signatureColumn, createdByColumn)
+	columns := make([]csvColumn, 0, len(names))
+	for _, name := range names {
+		i := slices.IndexFunc(available, func(col csvColumn) bool { return strings.EqualFold(col.Name, name) })
+		if i < 0 {
+			valid := make([]string, len(available))
+			for j, col := range available {
+				valid[j] = col.Name
+			}
+			return nil, fmt.Errorf("unknown field %q (available: %s)", name, strings.Join(valid, ", "))
+		}
+		columns = append(columns, available[i])
+	}
+	return columns, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestGenerateCSVKeepsColumnsAlignedWithSeveralLabels(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "items.csv")
+	items := []ProjectItem{
+		{ID: "1", Title: "Several labels", Labels: []string{"bug", "help wanted", "priority: high"}, Recipient: "0xabc", BountyAmount: "500", BountySymbol: "BUIDL"},
+		{ID: "2", Title: "No labels", Recipient: "0xdef", BountyAmount: "250", BountySymbol: "BUIDL"},
+	}
+	if err := generateCSV(items, filename, csvOptions{}); err != nil {
+		t.Fatalf("generateCSV: %v", err)
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("reading the CSV back: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want a header and 2 rows", len(records))
+	}
+	header := records[0]
+	column := func(name string) int {
+		i := slices.Index(header, name)
+		if i < 0 {
+			t.Fatalf("no %q column in %v", name, header)
+		}
+		return i
+	}
+	for i, want := range []struct{ labels, recipient, bounty string }{
+		{"bug;help wanted;priority: high", "0xabc", "500"},
+		{"", "0xdef", "250"},
+	} {
+		row := records[i+1]
+		if len(row) != len(header) {
+			t.Fatalf("row %d has %d fields, want %d", i+1, len(row), len(header))
+		}
+		if row[column("Labels")] != want.labels || row[column("Recipient")] != want.recipient || row[column("Bounty Amount")] != want.bounty {
+			t.Errorf("row %d = %v, want labels %q, recipient %q and bounty %q", i+1, row, want.labels, want.recipient, want.bounty)
+		}
+	}
+}
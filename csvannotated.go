@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// csvColumnDescriptions explains the source of each column, for the second
+// header row of the csv-annotated format.
+var csvColumnDescriptions = map[string]string{
+	"ID":              "GitHub node ID of the project item",
+	"Title":           "Issue or pull request title",
+	"URL":             "Issue or pull request URL",
+	"Created At":      "When the issue or pull request was opened",
+	"Updated At":      "When the issue or pull request was last updated",
+	"Due Date":        "Due date project field",
+	"Assigned To":     "GitHub logins of the assignees",
+	"Labels":          "Issue or pull request labels",
+	"Description":     "Issue or pull request body",
+	"Recipient":       "Ethereum wallet address from the Recipient project field",
+	"Bounty Amount":   "Bounty from the project's bounty field",
+	"Bounty Symbol":   "Token symbol of the bounty",
+	"Org":             "Organization the project belongs to",
+	"Project Number":  "Number of the project the item was exported from",
+	"Content Type":    "Whether the item is an issue or a pull request",
+	"Sprint":          "Iteration from --sprint-field",
+	"Signature Valid": "Whether the PGP signature in the body matches the first assignee's GitHub GPG keys",
+	"Assignee":        "GitHub login of the assignee this row represents",
+	"assignee_index":  "Position of Assignee among the item's assignees",
+	rowHashColumn:     "SHA-256 of the row's other values",
+	"Assignee N":      "GitHub login of the Nth assignee",
+	"AssignedTo_N":    "GitHub login of the Nth assignee",
+	"Labels_N":        "Nth label",
+}
+
+// csvAnnotations returns the second header row of the csv-annotated format:
+// the description and type of each column, with a leading "# " so parsers
+// that support comment lines skip it.
+func csvAnnotations(header, types []string) []string {
+	annotations := make([]string, len(header))
+	for i, name := range header {
+		// Numbered columns such as Assignee 2 or Labels_3 share one
+		// description.
+		if trimmed := strings.TrimRightFunc(name, unicode.IsDigit); trimmed != name {
+			name = trimmed + "N"
+		}
+		annotations[i] = csvColumnDescriptions[name] + " (" + types[i] + ")"
+	}
+	if len(annotations) > 0 {
+		annotations[0] = "# " + annotations[0]
+	}
+	return annotations
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// base64Columns are the user-controlled CSV columns that the csv-base64
+// format encodes, so a value such as =HYPERLINK(...) cannot be evaluated as
+// a spreadsheet formula. Base64 of UTF-8 text never starts with =, +, - or
+// @. The encoded columns are marked with base64HeaderSuffix.
+var base64Columns = []string{"Title", "Description", "Recipient"}
+
+const base64HeaderSuffix = " (base64)"
+
+// encodeBase64Columns returns columns with the base64Columns replaced by
+// their base64-encoded form.
+func encodeBase64Columns(columns []csvColumn) []csvColumn {
+	columns = slices.Clone(columns)
+	for i, col := range columns {
+		if slices.Contains(base64Columns, col.Name) {
+			value := col.Value
+			columns[i] = csvColumn{col.Name + base64HeaderSuffix, col.Type, func(item ProjectItem) string {
+				return base64.StdEncoding.EncodeToString([]byte(value(item)))
+			}}
+		}
+	}
+	return columns
+}
+
+// decodeCSVBase64 reads a csv-base64 export and writes it to w as a plain
+// CSV, decoding every column whose header ends in base64HeaderSuffix.
+func decodeCSVBase64(filename string, w io.Writer) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("reading header: %w", err)
+	}
+	var encoded []int
+	for i, name := range header {
+		if base, ok := strings.CutSuffix(name, base64HeaderSuffix); ok {
+			header[i] = base
+			encoded = append(encoded, i)
+		}
+	}
+	if len(encoded) == 0 {
+		return fmt.Errorf("no %q columns; was the file written with --format csv-base64?", "*"+base64HeaderSuffix)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		for _, i := range encoded {
+			value, err := base64.StdEncoding.DecodeString(record[i])
+			if err != nil {
+				line, _ := reader.FieldPos(i)
+				return fmt.Errorf("line %d: %s is not valid base64: %w", line, header[i], err)
+			}
+			record[i] = string(value)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
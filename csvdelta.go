@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+)
+
+// deltaFormat is the CSV of items that changed since the previous export in
+// --cache-db. It is generated by a job of its own in run, because the
+// previous export has to be read before a sqlite job in the same run
+// replaces it.
+const deltaFormat = "csv-delta"
+
+// Values of the change_type column of the csv-delta format.
+const (
+	changeAdded   = "added"
+	changeUpdated = "updated"
+)
+
+// loadPreviousExport returns the items of the latest export in the sqlite
+// database filename. A missing database means there was no previous run,
+// so every item counts as added.
+func loadPreviousExport(filename string) ([]ProjectItem, error) {
+	items, exportedAt, err := loadSQLiteItems(filename, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		slog.Info("No previous export for csv-delta; every item is added", "cache_db", filename)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Comparing with the previous export", "cache_db", filename, "exported_at", exportedAt)
+	return items, nil
+}
+
+// generateCSVDelta writes the CSV columns of the items that are not in
+// previous, or whose CSV values differ from their previous ones, followed by
+// a change_type column saying which. Unchanged items are left out. Unless
+// baseline is empty, the items are then written to the sqlite database
+// baseline in mode, so the next delta compares with this export.
+func generateCSVDelta(items, previous []ProjectItem, filename, baseline, mode string) error {
+	before := make(map[string][]string, len(previous))
+	for _, item := range previous {
+		before[item.ID] = csvRow(item)
+	}
+
+	file, err := createOutput(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Abort()
+
+	writer := csv.NewWriter(file)
+	header := make([]string, 0, len(csvColumns)+1)
+	for _, col := range csvColumns {
+		header = append(header, col.Name)
+	}
+	if err := writer.Write(append(header, "change_type")); err != nil {
+		return err
+	}
+	for _, item := range items {
+		row := csvRow(item)
+		change := changeAdded
+		if old, ok := before[item.ID]; ok {
+			if slices.Equal(old, row) {
+				continue
+			}
+			change = changeUpdated
+		}
+		if err := writer.Write(append(row, change)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("writing %s: %w", filename, err)
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if baseline == "" {
+		return nil
+	}
+	if err := generateSQLite(items, baseline, mode); err != nil {
+		return fmt.Errorf("recording the export in %s: %w", baseline, err)
+	}
+	return nil
+}
+
+// csvRow returns the values of item for csvColumns.
+func csvRow(item ProjectItem) []string {
+	row := make([]string, len(csvColumns))
+	for i, col := range csvColumns {
+		row[i] = col.Value(item)
+	}
+	return row
+}
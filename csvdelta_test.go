@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGenerateCSVDeltaRecordsItsBaseline(t *testing.T) {
+	dir := t.TempDir()
+	cacheDB := filepath.Join(dir, "cache.db")
+	delta := func(items []ProjectItem) []string {
+		t.Helper()
+		previous, err := loadPreviousExport(cacheDB)
+		if err != nil {
+			t.Fatalf("loadPreviousExport: %v", err)
+		}
+		filename := filepath.Join(dir, "delta.csv")
+		if err := generateCSVDelta(items, previous, filename, cacheDB, sqliteOverwrite); err != nil {
+			t.Fatalf("generateCSVDelta: %v", err)
+		}
+		file, err := os.Open(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		records, err := csv.NewReader(file).ReadAll()
+		if err != nil {
+			t.Fatalf("reading the delta back: %v", err)
+		}
+		var changes []string
+		for _, record := range records[1:] {
+			changes = append(changes, record[len(record)-1])
+		}
+		return changes
+	}
+
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	items := []ProjectItem{
+		{ID: "1", Title: "First", URL: "https://github.com/NautilusOSS/repo/issues/1", CreatedAt: createdAt, UpdatedAt: createdAt, BountyAmount: "500", BountySymbol: "BUIDL"},
+		{ID: "2", Title: "Second", URL: "https://github.com/NautilusOSS/repo/issues/2", CreatedAt: createdAt, UpdatedAt: createdAt, BountyAmount: "250", BountySymbol: "BUIDL"},
+	}
+	if got := delta(items); len(got) != 2 || got[0] != changeAdded || got[1] != changeAdded {
+		t.Fatalf("first delta = %v, want both items added", got)
+	}
+	if got := delta(items); len(got) != 0 {
+		t.Errorf("second delta of the same items = %v, want no changes", got)
+	}
+	items[1].BountyAmount = "300"
+	if got := delta(items); len(got) != 1 || got[0] != changeUpdated {
+		t.Errorf("third delta = %v, want the changed item updated", got)
+	}
+}
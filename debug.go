@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// withInsecureTLS returns a context whose oauth2 clients skip TLS certificate
+// verification, for local GHES instances and mock servers with self-signed
+// certificates.
+func withInsecureTLS(ctx context.Context) context.Context {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	return context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: transport})
+}
+
+// tracingTransport logs DNS lookup, connection, TLS handshake and
+// time-to-first-byte timings to stderr for every request it sends. Each
+// request gets its own httptrace.ClientTrace, so requests sent concurrently
+// do not mix up each other's timestamps.
+type tracingTransport struct {
+	next http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var start, dnsStart, connectStart, tlsStart time.Time
+
+	trace := &httptrace.ClientTrace{
+		GetConn: func(hostPort string) {
+			start = time.Now()
+			slog.Debug("Requesting connection", "host", hostPort)
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			dnsStart = time.Now()
+		},
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			slog.Debug("DNS lookup", "duration", time.Since(dnsStart), "addrs", info.Addrs, "err", info.Err)
+		},
+		ConnectStart: func(network, addr string) {
+			connectStart = time.Now()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			slog.Debug("Connect", "network", network, "addr", addr, "duration", time.Since(connectStart), "err", err)
+		},
+		TLSHandshakeStart: func() {
+			tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			slog.Debug("TLS handshake", "duration", time.Since(tlsStart), "version", tls.VersionName(state.Version), "err", err)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			slog.Debug("Got connection", "reused", info.Reused, "idle", info.IdleTime)
+		},
+		GotFirstResponseByte: func() {
+			slog.Debug("First response byte", "duration", time.Since(start))
+		},
+	}
+	return t.next.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+}
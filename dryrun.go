@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// dryRun is set by --dry-run. Output files are then printed to stdout
+// instead of being created.
+var dryRun bool
+
+// dryRunMu keeps the files printed by concurrent exports from interleaving.
+var dryRunMu sync.Mutex
+
+// outputFile is an output file being written by a generator. Close commits
+// the file and reports any error in doing so; Abort, deferred right after
+// the file is opened, discards it when the generator fails before Close.
+type outputFile interface {
+	io.Writer
+	Close() error
+	Abort()
+}
+
+// dryRunFile buffers one output file and prints it on Close, after a
+// "--- <filename> ---" header.
+type dryRunFile struct {
+	bytes.Buffer
+	filename string
+	closed   bool
+}
+
+func (f *dryRunFile) Close() error {
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	dryRunMu.Lock()
+	defer dryRunMu.Unlock()
+	fmt.Fprintf(os.Stdout, "--- %s ---\n", f.filename)
+	_, err := f.WriteTo(os.Stdout)
+	return err
+}
+
+// Abort drops the buffered file unless Close was called first.
+func (f *dryRunFile) Abort() {
+	f.closed = true
+}
+
+// appendFile is an output file opened for appending. Rows already appended
+// cannot be taken back, so Abort only closes it.
+type appendFile struct {
+	file *os.File
+	// err is the first write error; it is returned by Close.
+	err    error
+	closed bool
+}
+
+func (f *appendFile) Write(p []byte) (int, error) {
+	n, err := f.file.Write(p)
+	if err != nil && f.err == nil {
+		f.err = err
+	}
+	return n, err
+}
+
+func (f *appendFile) Close() error {
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	err := f.err
+	if closeErr := f.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (f *appendFile) Abort() {
+	f.Close()
+}
+
+// createOutput creates or replaces the output file filename. The file only
+// appears once the returned writer is closed; see atomicFile. Under
+// --dry-run nothing is created and the returned writer prints to stdout.
+func createOutput(filename string) (outputFile, error) {
+	if dryRun {
+		return &dryRunFile{filename: filename}, nil
+	}
+	return newAtomicFile(filename)
+}
+
+// appendOutput is like createOutput but appends to an existing file.
+func appendOutput(filename string) (outputFile, error) {
+	if dryRun {
+		return &dryRunFile{filename: filename}, nil
+	}
+	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &appendFile{file: file}, nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+)
+
+// newErrorLogHandler returns the handler of --error-log: warnings and
+// errors as JSON lines of the form {"time","level","message",...}, with
+// the record's attributes as further keys and level "warning" or "error".
+// The debug and info output of --log-level never reaches the file.
+func newErrorLogHandler(w io.Writer) slog.Handler {
+	return slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level: slog.LevelWarn,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) > 0 {
+				return a
+			}
+			switch a.Key {
+			case slog.MessageKey:
+				a.Key = "message"
+			case slog.LevelKey:
+				level := "error"
+				if a.Value.Any().(slog.Level) < slog.LevelError {
+					level = "warning"
+				}
+				a.Value = slog.StringValue(level)
+			}
+			return a
+		},
+	})
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// dateOnly is the YYYY-MM-DD layout accepted by --updated-after and
+// --updated-before in addition to RFC 3339.
+const dateOnly = "2006-01-02"
+
+// mostRecent returns the n most recently updated items, newest first.
+func mostRecent(items []ProjectItem, n int) []ProjectItem {
+	sorted := make([]ProjectItem, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].UpdatedAt.After(sorted[j].UpdatedAt)
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// updatedAfter returns the items updated strictly after t.
+func updatedAfter(items []ProjectItem, t time.Time) []ProjectItem {
+	var filtered []ProjectItem
+	for _, item := range items {
+		if item.UpdatedAt.After(t) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// updatedWithin returns the items updated in the window [after, before].
+// Both bounds are inclusive and a zero bound is open.
+func updatedWithin(items []ProjectItem, after, before time.Time) []ProjectItem {
+	var filtered []ProjectItem
+	for _, item := range items {
+		if !after.IsZero() && item.UpdatedAt.Before(after) {
+			continue
+		}
+		if !before.IsZero() && item.UpdatedAt.After(before) {
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+	return filtered
+}
+
+// parseTimeBound parses an RFC 3339 timestamp or a YYYY-MM-DD date in UTC.
+// A date used as an upper bound stands for the last instant of that day, so
+// items updated at any time on it are still included.
+func parseTimeBound(s string, upper bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(dateOnly, s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if upper {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUpdatedWithinBounds(t *testing.T) {
+	mustParse := func(s string, upper bool) time.Time {
+		t.Helper()
+		bound, err := parseTimeBound(s, upper)
+		if err != nil {
+			t.Fatalf("parseTimeBound(%q): %v", s, err)
+		}
+		return bound
+	}
+	tests := []struct {
+		name      string
+		updatedAt string
+		after     string
+		before    string
+		included  bool
+	}{
+		{name: "exactly on the lower bound", updatedAt: "2024-03-01T12:00:00Z", after: "2024-03-01T12:00:00Z", included: true},
+		{name: "just before the lower bound", updatedAt: "2024-03-01T11:59:59Z", after: "2024-03-01T12:00:00Z"},
+		{name: "exactly on the upper bound", updatedAt: "2024-03-31T12:00:00Z", before: "2024-03-31T12:00:00Z", included: true},
+		{name: "just after the upper bound", updatedAt: "2024-03-31T12:00:01Z", before: "2024-03-31T12:00:00Z"},
+		{name: "date-only lower bound at midnight", updatedAt: "2024-03-01T00:00:00Z", after: "2024-03-01", included: true},
+		{name: "end of a date-only upper bound", updatedAt: "2024-03-31T23:59:59Z", before: "2024-03-31", included: true},
+		{name: "day after a date-only upper bound", updatedAt: "2024-04-01T00:00:00Z", before: "2024-03-31"},
+		{name: "inside both bounds", updatedAt: "2024-03-15T00:00:00Z", after: "2024-03-01", before: "2024-03-31", included: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var after, before time.Time
+			if tt.after != "" {
+				after = mustParse(tt.after, false)
+			}
+			if tt.before != "" {
+				before = mustParse(tt.before, true)
+			}
+			updatedAt, err := time.Parse(time.RFC3339, tt.updatedAt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := len(updatedWithin([]ProjectItem{{UpdatedAt: updatedAt}}, after, before)) == 1
+			if got != tt.included {
+				t.Errorf("included = %v, want %v", got, tt.included)
+			}
+		})
+	}
+}
+
+func TestParseTimeBoundDateOnlyUpperBound(t *testing.T) {
+	got, err := parseTimeBound("2024-02-29", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2024, time.February, 29, 23, 59, 59, 999999999, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("parseTimeBound = %v, want %v", got, want)
+	}
+}
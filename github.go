@@ -0,0 +1,588 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// fetchItems queries each organization's project and merges the results.
+// Items are consumed from getProjectItems as each page is parsed, or as each
+// project completes when there are several (see produceItems); with
+// --format ndjson-stream they are also written to stdout right away, before
+// any later filtering, checks or file generation.
+func fetchItems(ctx context.Context, client GraphQLClient, opts *options) ([]ProjectItem, error) {
+	out := make(chan ProjectItem)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(out)
+		errc <- produceItems(ctx, client, opts, out)
+	}()
+
+	var stream *json.Encoder
+	if opts.streamItems {
+		stream = json.NewEncoder(os.Stdout)
+	}
+
+	var items []ProjectItem
+	for item := range out {
+		if opts.secretScanning {
+			redactSecrets(&item)
+		}
+		if stream != nil {
+			if err := stream.Encode(item); err != nil {
+				slog.Warn("Stopped writing ndjson stream", "err", err)
+				stream = nil
+			}
+		}
+		items = append(items, item)
+	}
+	if err := <-errc; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// produceItems sends the matching items of every project in opts.projects
+// to out. A project that cannot be exported is logged and skipped so the
+// others are still exported; it is an error only if no project succeeds.
+// With several projects, each project's items are held back until all its
+// pages are fetched, so a project that fails partway is left out entirely
+// rather than exported in part.
+func produceItems(ctx context.Context, client GraphQLClient, opts *options, out chan<- ProjectItem) error {
+	if len(opts.projects) == 1 {
+		return produceProjectItems(ctx, client, opts.projects[0], opts, out)
+	}
+	var errs []error
+	for _, ref := range opts.projects {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		items, err := collectProjectItems(ctx, client, ref, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			slog.Error("Skipping project", "org", ref.org, "project_number", ref.number, "err", err, "discarded_items", len(items))
+			errs = append(errs, err)
+			continue
+		}
+		for _, item := range items {
+			select {
+			case out <- item:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	if len(errs) == len(opts.projects) {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// collectProjectItems returns the matching items of a single project. On
+// error it also returns the items fetched before the failure.
+func collectProjectItems(ctx context.Context, client GraphQLClient, ref projectRef, opts *options) ([]ProjectItem, error) {
+	ch := make(chan ProjectItem)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		errc <- produceProjectItems(ctx, client, ref, opts, ch)
+	}()
+	var items []ProjectItem
+	for item := range ch {
+		items = append(items, item)
+	}
+	return items, <-errc
+}
+
+// produceProjectItems sends the matching items of a single project to out.
+func produceProjectItems(ctx context.Context, client GraphQLClient, ref projectRef, opts *options, out chan<- ProjectItem) error {
+	org, projectNumber := ref.org, ref.number
+	// --project-id skips the lookup; parseFlags rejects the checks that
+	// need the metadata it returns.
+	project := projectInfo{ID: opts.projectID}
+	if project.ID == "" {
+		getProject := getProjectID
+		if opts.ownerType == ownerTypeUser {
+			getProject = getUserProjectID
+		}
+		var err error
+		if project, err = getProject(ctx, client, org, projectNumber); err != nil {
+			return fmt.Errorf("error getting project ID for %s: %w", org, err)
+		}
+		fmt.Fprintf(statusOut, "Project ID (%s): %s\n", org, project.ID)
+	}
+	if opts.checkProjectStatus && project.Closed {
+		return fmt.Errorf("project %d of %s is closed; refusing to export from an archived project", projectNumber, org)
+	}
+	if opts.maxProjectAgeHours > 0 {
+		if age := time.Since(project.UpdatedAt); age > time.Duration(opts.maxProjectAgeHours)*time.Hour {
+			return fmt.Errorf("project %d of %s was last modified %s (%.0f hours ago, limit %d); check that this is the right project",
+				projectNumber, org, project.UpdatedAt.Format(time.RFC3339), age.Hours(), opts.maxProjectAgeHours)
+		}
+	}
+	if len(opts.requiredFields) > 0 {
+		fields, err := getProjectFields(ctx, client, project.ID)
+		if err != nil {
+			return fmt.Errorf("error getting project fields for %s: %w", org, err)
+		}
+		for _, name := range opts.requiredFields {
+			if !slices.Contains(fields, name) {
+				return fmt.Errorf("project %d of %s has no field %q (available: %s)", projectNumber, org, name, strings.Join(fields, ", "))
+			}
+		}
+	}
+
+	if err := getProjectItems(ctx, client, ref, project.ID, opts, out); err != nil {
+		return fmt.Errorf("error getting project items for %s: %w", org, err)
+	}
+	return nil
+}
+
+// projectRef identifies a project to export from by its owner and number.
+type projectRef struct {
+	org    string
+	number int
+}
+
+// parseProjectRefs parses --orgs entries of the form ORG:PROJECT_NUMBER,
+// such as NautilusOSS:2.
+func parseProjectRefs(entries []string) ([]projectRef, error) {
+	refs := make([]projectRef, 0, len(entries))
+	for _, entry := range entries {
+		org, number, ok := strings.Cut(entry, ":")
+		n, err := strconv.Atoi(number)
+		if !ok || org == "" || err != nil || n < 1 {
+			return nil, fmt.Errorf("%q is not ORG:PROJECT_NUMBER", entry)
+		}
+		refs = append(refs, projectRef{org: org, number: n})
+	}
+	return refs, nil
+}
+
+// Owner types accepted by --owner-type.
+const (
+	ownerTypeOrg  = "org"
+	ownerTypeUser = "user"
+)
+
+// projectInfo is the project metadata returned by getProjectID.
+type projectInfo struct {
+	ID        string
+	Closed    bool
+	UpdatedAt time.Time
+}
+
+func getProjectID(ctx context.Context, client GraphQLClient, org string, projectNumber int) (projectInfo, error) {
+	var query struct {
+		Organization struct {
+			ProjectV2 projectInfo `graphql:"projectV2(number: $number)"`
+		} `graphql:"organization(login: $login)"`
+	}
+
+	variables := map[string]interface{}{
+		"login":  githubv4.String(org),
+		"number": githubv4.Int(projectNumber),
+	}
+
+	err := queryWithRetry(ctx, client, &query, variables, maxQueryRetries)
+	if err != nil {
+		return projectInfo{}, err
+	}
+
+	return query.Organization.ProjectV2, nil
+}
+
+// getProjectFields returns the names of the fields defined in a project.
+func getProjectFields(ctx context.Context, client GraphQLClient, projectID string) ([]string, error) {
+	var query struct {
+		Node struct {
+			ProjectV2 struct {
+				Fields struct {
+					Nodes []struct {
+						Common struct {
+							Name string
+						} `graphql:"... on ProjectV2FieldCommon"`
+					}
+				} `graphql:"fields(first: 100)"`
+			} `graphql:"... on ProjectV2"`
+		} `graphql:"node(id: $id)"`
+	}
+
+	variables := map[string]interface{}{
+		"id": githubv4.ID(projectID),
+	}
+
+	if err := queryWithRetry(ctx, client, &query, variables, maxQueryRetries); err != nil {
+		return nil, err
+	}
+	names := make([]string, len(query.Node.ProjectV2.Fields.Nodes))
+	for i, node := range query.Node.ProjectV2.Fields.Nodes {
+		names[i] = node.Common.Name
+	}
+	return names, nil
+}
+
+// fieldValueNode is a single value of a project item's fieldValues
+// connection.
+type fieldValueNode struct {
+	// We need to use fragments for union types
+	Status struct {
+		Name  string
+		Field struct {
+			SingleSelectField struct {
+				Name string
+			} `graphql:"... on ProjectV2SingleSelectField"`
+		}
+	} `graphql:"... on ProjectV2ItemFieldSingleSelectValue"`
+	Text struct {
+		Text  string
+		Field fieldName
+	} `graphql:"... on ProjectV2ItemFieldTextValue"`
+	Number struct {
+		Number float64
+		Field  fieldName
+	} `graphql:"... on ProjectV2ItemFieldNumberValue"`
+	Date struct {
+		Date  string
+		Field fieldName
+	} `graphql:"... on ProjectV2ItemFieldDateValue"`
+	Iteration struct {
+		Title string
+		Field struct {
+			IterationField struct {
+				Name string
+			} `graphql:"... on ProjectV2IterationField"`
+		}
+	} `graphql:"... on ProjectV2ItemFieldIterationValue"`
+}
+
+// fieldName is the field a project item field value belongs to.
+type fieldName struct {
+	Common struct {
+		Name string
+	} `graphql:"... on ProjectV2FieldCommon"`
+}
+
+// getUserProjectID is getProjectID for a project owned by a personal account
+// rather than an organization.
+func getUserProjectID(ctx context.Context, client GraphQLClient, login string, projectNumber int) (projectInfo, error) {
+	var query struct {
+		User struct {
+			ProjectV2 projectInfo `graphql:"projectV2(number: $number)"`
+		} `graphql:"user(login: $login)"`
+	}
+
+	variables := map[string]interface{}{
+		"login":  githubv4.String(login),
+		"number": githubv4.Int(projectNumber),
+	}
+
+	if err := queryWithRetry(ctx, client, &query, variables, maxQueryRetries); err != nil {
+		return projectInfo{}, err
+	}
+	return query.User.ProjectV2, nil
+}
+
+// projectItemNode is a single item of a ProjectV2 items connection.
+type projectItemNode struct {
+	ID          string
+	FieldValues struct {
+		PageInfo struct {
+			HasNextPage bool
+			EndCursor   githubv4.String
+		}
+		Nodes []fieldValueNode
+	} `graphql:"fieldValues(first: 100)"`
+	Content struct {
+		Issue       itemContent `graphql:"... on Issue"`
+		PullRequest itemContent `graphql:"... on PullRequest"`
+		// IssueLinks is only queried with --check-linked-pr.
+		IssueLinks struct {
+			TimelineItems struct {
+				Nodes []struct {
+					CrossReferencedEvent struct {
+						Source struct {
+							PullRequest struct {
+								URL string
+							} `graphql:"... on PullRequest"`
+						}
+					} `graphql:"... on CrossReferencedEvent"`
+				}
+			} `graphql:"timelineItems(first: 10, itemTypes: [CROSS_REFERENCED_EVENT])"`
+		} `graphql:"... on Issue @include(if: $linkedPRs)"`
+	}
+}
+
+// itemContent holds the fields read from an item's issue or pull request.
+type itemContent struct {
+	Title     string
+	URL       string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Body      string
+	Author    struct {
+		Login string
+	}
+	Assignees struct {
+		Nodes []struct {
+			Login string
+		}
+	} `graphql:"assignees(first: 100)"`
+	Labels struct {
+		Nodes []struct {
+			Name  string
+			Color string
+		}
+	} `graphql:"labels(first: 100)"`
+}
+
+// Content types of a ProjectItem.
+const (
+	contentTypeIssue       = "issue"
+	contentTypePullRequest = "pull_request"
+	// Draft issues exist only in the project and have no URL.
+	contentTypeDraftIssue = "draft_issue"
+)
+
+// getProjectItems pages through the items of a project and sends each one
+// that matches the filters in opts to out as soon as its page is parsed. It
+// does not close out.
+func getProjectItems(ctx context.Context, client GraphQLClient, ref projectRef, projectID string, opts *options, out chan<- ProjectItem) error {
+	var query struct {
+		Node struct {
+			ProjectV2 struct {
+				Items struct {
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   githubv4.String
+					}
+					Nodes []projectItemNode
+				} `graphql:"items(first: $first, after: $cursor)"`
+			} `graphql:"... on ProjectV2"`
+		} `graphql:"node(id: $id)"`
+	}
+
+	variables := map[string]interface{}{
+		"id":        githubv4.ID(projectID),
+		"first":     githubv4.Int(opts.firstN),
+		"cursor":    (*githubv4.String)(nil),
+		"linkedPRs": githubv4.Boolean(opts.checkLinkedPR),
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := queryWithRetry(ctx, client, &query, variables, maxQueryRetries)
+		if err != nil {
+			return err
+		}
+
+		page := query.Node.ProjectV2.Items
+		for _, node := range page.Nodes {
+			if node.FieldValues.PageInfo.HasNextPage {
+				rest, err := getRemainingFieldValues(ctx, client, node.ID, node.FieldValues.PageInfo.EndCursor)
+				if err != nil {
+					return fmt.Errorf("error getting field values of item %s: %w", node.ID, err)
+				}
+				node.FieldValues.Nodes = append(node.FieldValues.Nodes, rest...)
+			}
+			item, ok := parseItemNode(node, opts)
+			if !ok {
+				continue
+			}
+			item.Org = ref.org
+			item.ProjectNumber = ref.number
+			select {
+			case out <- item:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if opts.noPagination {
+			if len(page.Nodes) == opts.firstN {
+				slog.Warn("--no-pagination returned a full page; the project may contain more items that were not fetched", "item_count", opts.firstN)
+			}
+			break
+		}
+		if !page.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = githubv4.NewString(page.PageInfo.EndCursor)
+	}
+
+	return nil
+}
+
+// getRemainingFieldValues pages through the field values of a single project
+// item that follow cursor. Items rarely have more than 100 field values, so
+// this only runs for the few that do instead of paginating every item.
+func getRemainingFieldValues(ctx context.Context, client GraphQLClient, itemID string, cursor githubv4.String) ([]fieldValueNode, error) {
+	var query struct {
+		Node struct {
+			ProjectV2Item struct {
+				FieldValues struct {
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   githubv4.String
+					}
+					Nodes []fieldValueNode
+				} `graphql:"fieldValues(first: 100, after: $cursor)"`
+			} `graphql:"... on ProjectV2Item"`
+		} `graphql:"node(id: $id)"`
+	}
+
+	variables := map[string]interface{}{
+		"id":     githubv4.ID(itemID),
+		"cursor": githubv4.NewString(cursor),
+	}
+
+	var values []fieldValueNode
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := queryWithRetry(ctx, client, &query, variables, maxQueryRetries); err != nil {
+			return nil, err
+		}
+		page := query.Node.ProjectV2Item.FieldValues
+		values = append(values, page.Nodes...)
+		if !page.PageInfo.HasNextPage {
+			return values, nil
+		}
+		variables["cursor"] = githubv4.NewString(page.PageInfo.EndCursor)
+	}
+}
+
+// parseItemNode converts a project item node into a ProjectItem. It reports
+// false for items whose status is not one of opts.statuses or that are
+// excluded by --repo-filter. An empty opts.statuses matches every status.
+func parseItemNode(node projectItemNode, opts *options) (ProjectItem, bool) {
+	issue, contentType := node.Content.Issue, contentTypeIssue
+	if issue.URL == "" && node.Content.PullRequest.URL != "" {
+		issue, contentType = node.Content.PullRequest, contentTypePullRequest
+	} else if issue.URL == "" {
+		contentType = contentTypeDraftIssue
+	}
+	// An empty status filter accepts every item
+	matchesStatus := len(opts.statuses) == 0
+	var status string
+	var recipient string
+	var bountyAmount string
+	var bountySymbol string
+	var sprint string
+	var dueDate string
+
+	for _, fieldValue := range node.FieldValues.Nodes {
+		if slices.Contains(opts.statuses, fieldValue.Status.Name) {
+			matchesStatus = true
+			status = fieldValue.Status.Name
+		} else if len(opts.statuses) == 0 && fieldValue.Status.Field.SingleSelectField.Name == "Status" {
+			status = fieldValue.Status.Name
+		}
+		// Check for recipient field (text field)
+		if fieldValue.Text.Text != "" {
+			// With --bounty-field-name the bounty field is known by name;
+			// otherwise a text value ending in the symbol is taken as one
+			if opts.bountyFieldName != "" && fieldValue.Text.Field.Common.Name == opts.bountyFieldName {
+				parts := strings.Fields(fieldValue.Text.Text)
+				switch len(parts) {
+				case 1:
+					bountyAmount, bountySymbol = parts[0], opts.bountySymbol
+				case 2:
+					bountyAmount, bountySymbol = parts[0], parts[1]
+				}
+			} else if opts.bountyFieldName == "" && strings.HasSuffix(strings.TrimSpace(fieldValue.Text.Text), opts.bountySymbol) {
+				parts := strings.Fields(fieldValue.Text.Text)
+				if len(parts) == 2 {
+					bountyAmount = parts[0]
+					bountySymbol = parts[1]
+				}
+			} else if !strings.Contains(fieldValue.Text.Text, opts.bountySymbol) {
+				// Only set as recipient if it's not a bounty value
+				recipient = fieldValue.Text.Text
+			}
+		}
+		if fieldValue.Date.Date != "" && fieldValue.Date.Field.Common.Name == opts.dueDateField {
+			dueDate = fieldValue.Date.Date
+		}
+		if opts.sprintField != "" && fieldValue.Iteration.Field.IterationField.Name == opts.sprintField {
+			sprint = fieldValue.Iteration.Title
+		}
+		// Keep the number field check as a fallback
+		if fieldValue.Number.Number > 0 && (opts.bountyFieldName == "" || fieldValue.Number.Field.Common.Name == opts.bountyFieldName) {
+			bountyAmount = strconv.FormatFloat(fieldValue.Number.Number, 'f', -1, 64)
+			bountySymbol = opts.bountySymbol
+		}
+	}
+
+	if !matchesStatus || !matchesRepoFilter(issue.URL, opts.repoFilters) {
+		return ProjectItem{}, false
+	}
+
+	assignees := make([]string, len(issue.Assignees.Nodes))
+	for i, a := range issue.Assignees.Nodes {
+		assignees[i] = a.Login
+	}
+	labels := make([]string, len(issue.Labels.Nodes))
+	labelColors := make(map[string]string, len(issue.Labels.Nodes))
+	for i, l := range issue.Labels.Nodes {
+		labels[i] = l.Name
+		labelColors[l.Name] = l.Color
+	}
+	var linkedPRs []string
+	for _, event := range node.Content.IssueLinks.TimelineItems.Nodes {
+		if u := event.CrossReferencedEvent.Source.PullRequest.URL; u != "" {
+			linkedPRs = append(linkedPRs, u)
+		}
+	}
+
+	return ProjectItem{
+		ID:                 node.ID,
+		Title:              issue.Title,
+		URL:                issue.URL,
+		CreatedAt:          issue.CreatedAt,
+		UpdatedAt:          issue.UpdatedAt,
+		DueDate:            dueDate,
+		CreatedBy:          issue.Author.Login,
+		AssignedTo:         assignees,
+		Labels:             labels,
+		LabelColors:        labelColors,
+		LinkedPullRequests: linkedPRs,
+		Description:        issue.Body,
+		ContentType:        contentType,
+		Recipient:          recipient,
+		BountyAmount:       bountyAmount,
+		BountySymbol:       bountySymbol,
+		Status:             status,
+		Sprint:             sprint,
+	}, true
+}
+
+// matchesRepoFilter reports whether url belongs to one of repos, given as
+// OWNER/REPO. An empty filter matches every URL.
+func matchesRepoFilter(url string, repos []string) bool {
+	if len(repos) == 0 {
+		return true
+	}
+	url = strings.ToLower(url)
+	for _, repo := range repos {
+		if strings.Contains(url, "/"+strings.ToLower(strings.Trim(repo, "/"))+"/") {
+			return true
+		}
+	}
+	return false
+}
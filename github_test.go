@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// newTestGraphQLClient starts a mock GraphQL server that answers every query
+// with the JSON document returned by respond, and returns a client for it.
+func newTestGraphQLClient(t *testing.T, respond func(r *http.Request, query string, variables map[string]any) string) GraphQLClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, respond(r, body.Query, body.Variables))
+	}))
+	t.Cleanup(srv.Close)
+	return githubv4.NewEnterpriseClient(srv.URL, srv.Client())
+}
+
+// testItemNode returns the JSON of a project item in the given status.
+func testItemNode(id, status string) string {
+	return fmt.Sprintf(`{"id":%q,"fieldValues":{"pageInfo":{"hasNextPage":false},"nodes":[{"name":%q}]},
+		"content":{"title":"Item %s","url":"https://github.com/NautilusOSS/repo/issues/%s"}}`, id, status, id, id)
+}
+
+// testItemsPage returns the JSON response to a project items query.
+func testItemsPage(hasNextPage bool, cursor string, nodes ...string) string {
+	return fmt.Sprintf(`{"data":{"node":{"items":{"pageInfo":{"hasNextPage":%t,"endCursor":%q},"nodes":[%s]}}}}`,
+		hasNextPage, cursor, strings.Join(nodes, ","))
+}
+
+func TestFetchItemsDropsPartiallyFetchedProject(t *testing.T) {
+	client := newTestGraphQLClient(t, func(_ *http.Request, query string, variables map[string]any) string {
+		if strings.Contains(query, "organization(login: $login)") {
+			return fmt.Sprintf(`{"data":{"organization":{"projectV2":{"id":"PVT_%s"}}}}`, variables["login"])
+		}
+		switch {
+		case variables["id"] == "PVT_Broken" && variables["cursor"] == nil:
+			return testItemsPage(true, "page2", testItemNode("broken-1", "Pending Payment"))
+		case variables["id"] == "PVT_Broken":
+			return `{"errors":[{"message":"something went wrong"}]}`
+		default:
+			return testItemsPage(false, "", testItemNode("ok-1", "Pending Payment"))
+		}
+	})
+	opts := &options{
+		firstN:   100,
+		statuses: stringList{"Pending Payment"},
+		projects: []projectRef{{org: "Broken", number: 1}, {org: "Working", number: 2}},
+	}
+
+	items, err := fetchItems(context.Background(), client, opts)
+	if err != nil {
+		t.Fatalf("fetchItems: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != "ok-1" {
+		t.Errorf("fetchItems returned %+v, want only the item of the working project", items)
+	}
+}
+
+func TestParseItemNodeReadsDueDateField(t *testing.T) {
+	var node projectItemNode
+	node.ID = "PVTI_1"
+	node.Content.Issue.URL = "https://github.com/NautilusOSS/repo/issues/1"
+	var status, due, other fieldValueNode
+	status.Status.Name = "Pending Payment"
+	due.Date.Date = "2024-03-31"
+	due.Date.Field.Common.Name = "Payout Date"
+	other.Date.Date = "2024-01-01"
+	other.Date.Field.Common.Name = "Start Date"
+	node.FieldValues.Nodes = []fieldValueNode{status, other, due}
+
+	item, ok := parseItemNode(node, &options{statuses: stringList{"Pending Payment"}, bountySymbol: "BUIDL", dueDateField: "Payout Date"})
+	if !ok {
+		t.Fatal("parseItemNode rejected the item")
+	}
+	if item.DueDate != "2024-03-31" {
+		t.Errorf("DueDate = %q, want the value of the Payout Date field", item.DueDate)
+	}
+}
+
+func TestParseItemNodeKeepsFractionalNumberBounty(t *testing.T) {
+	var node projectItemNode
+	node.ID = "PVTI_1"
+	node.Content.Issue.URL = "https://github.com/NautilusOSS/repo/issues/1"
+	var status, bounty fieldValueNode
+	status.Status.Name = "Pending Payment"
+	bounty.Number.Number = 1.5
+	node.FieldValues.Nodes = []fieldValueNode{status, bounty}
+
+	item, ok := parseItemNode(node, &options{statuses: stringList{"Pending Payment"}, bountySymbol: "BUIDL"})
+	if !ok {
+		t.Fatal("parseItemNode rejected the item")
+	}
+	if item.BountyAmount != "1.5" {
+		t.Errorf("BountyAmount = %q, want 1.5", item.BountyAmount)
+	}
+	if findings := checkBountyInteger([]ProjectItem{item}); len(findings) != 1 {
+		t.Errorf("checkBountyInteger returned %v, want one finding for the fractional bounty", findings)
+	}
+}
+
+func TestFetchItemsStopsWhenCancelledMidPagination(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := newTestGraphQLClient(t, func(r *http.Request, _ string, variables map[string]any) string {
+		if variables["cursor"] == nil {
+			return testItemsPage(true, "page2", testItemNode("1", "Pending Payment"))
+		}
+		// Simulate Ctrl-C while the second page is in flight.
+		cancel()
+		<-r.Context().Done()
+		return testItemsPage(false, "", testItemNode("2", "Pending Payment"))
+	})
+	opts := &options{
+		firstN:    100,
+		statuses:  stringList{"Pending Payment"},
+		projectID: "PVT_test",
+		projects:  []projectRef{{org: "NautilusOSS", number: 2}},
+	}
+
+	start := time.Now()
+	_, err := fetchItems(ctx, client, opts)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("fetchItems = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("fetchItems took %v to return after cancellation", elapsed)
+	}
+}
+
+func TestGetProjectItemsFollowsItemAndFieldValuePages(t *testing.T) {
+	client := newTestGraphQLClient(t, func(_ *http.Request, query string, variables map[string]any) string {
+		if strings.Contains(query, "... on ProjectV2Item{") {
+			// The rest of item 1's field values: its bounty and recipient.
+			if variables["id"] != "1" || variables["cursor"] != "fv1" {
+				return `{"errors":[{"message":"unexpected field values query"}]}`
+			}
+			return `{"data":{"node":{"fieldValues":{"pageInfo":{"hasNextPage":false},"nodes":[{"text":"500 BUIDL"},{"text":"0xabc"}]}}}}`
+		}
+		if variables["cursor"] == nil {
+			first := `{"id":"1","fieldValues":{"pageInfo":{"hasNextPage":true,"endCursor":"fv1"},"nodes":[{"name":"Pending Payment"}]},
+				"content":{"title":"Item 1","url":"https://github.com/NautilusOSS/repo/issues/1"}}`
+			return testItemsPage(true, "page2", first)
+		}
+		if variables["cursor"] != "page2" {
+			return `{"errors":[{"message":"unexpected cursor"}]}`
+		}
+		return testItemsPage(false, "", testItemNode("2", "Pending Payment"), testItemNode("3", "Done"))
+	})
+	opts := &options{firstN: 1, statuses: stringList{"Pending Payment"}, bountySymbol: "BUIDL"}
+
+	out := make(chan ProjectItem, 10)
+	if err := getProjectItems(context.Background(), client, projectRef{org: "NautilusOSS", number: 2}, "PVT_test", opts, out); err != nil {
+		t.Fatalf("getProjectItems: %v", err)
+	}
+	close(out)
+	var items []ProjectItem
+	for item := range out {
+		items = append(items, item)
+	}
+
+	if len(items) != 2 || items[0].ID != "1" || items[1].ID != "2" {
+		t.Fatalf("got items %+v, want 1 and 2", items)
+	}
+	if items[0].BountyAmount != "500" || items[0].Recipient != "0xabc" {
+		t.Errorf("item 1 has bounty %q and recipient %q, want the values from its second field value page", items[0].BountyAmount, items[0].Recipient)
+	}
+}
+
+func TestParseItemNodeContentTypes(t *testing.T) {
+	var status fieldValueNode
+	status.Status.Name = "Pending Payment"
+	opts := &options{statuses: stringList{"Pending Payment"}, bountySymbol: "BUIDL"}
+
+	var issue projectItemNode
+	issue.ID = "PVTI_issue"
+	issue.FieldValues.Nodes = []fieldValueNode{status}
+	issue.Content.Issue.Title = "Fix the exporter"
+	issue.Content.Issue.URL = "https://github.com/NautilusOSS/repo/issues/1"
+	issue.Content.Issue.Author.Login = "alice"
+
+	var pr projectItemNode
+	pr.ID = "PVTI_pr"
+	pr.FieldValues.Nodes = []fieldValueNode{status}
+	pr.Content.PullRequest.Title = "Add the exporter"
+	pr.Content.PullRequest.URL = "https://github.com/NautilusOSS/repo/pull/2"
+	pr.Content.PullRequest.Author.Login = "bob"
+
+	var draft projectItemNode
+	draft.ID = "PVTI_draft"
+	draft.FieldValues.Nodes = []fieldValueNode{status}
+
+	tests := []struct {
+		node        projectItemNode
+		contentType string
+		title       string
+		url         string
+		createdBy   string
+	}{
+		{issue, contentTypeIssue, "Fix the exporter", "https://github.com/NautilusOSS/repo/issues/1", "alice"},
+		{pr, contentTypePullRequest, "Add the exporter", "https://github.com/NautilusOSS/repo/pull/2", "bob"},
+		{draft, contentTypeDraftIssue, "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			item, ok := parseItemNode(tt.node, opts)
+			if !ok {
+				t.Fatal("parseItemNode rejected the item")
+			}
+			if item.ContentType != tt.contentType || item.Title != tt.title || item.URL != tt.url || item.CreatedBy != tt.createdBy {
+				t.Errorf("got %s %q %s by %s, want %s %q %s by %s",
+					item.ContentType, item.Title, item.URL, item.CreatedBy, tt.contentType, tt.title, tt.url, tt.createdBy)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+// defaultRESTURL is the GitHub REST API that issues installation tokens.
+const defaultRESTURL = "https://api.github.com"
+
+// appTokenSource issues GitHub App installation tokens for --app-id,
+// --installation-id and --private-key-file. Installation tokens expire
+// after an hour, so wrap it in oauth2.ReuseTokenSource to fetch a new one
+// only when the current token runs out, e.g. during --watch.
+type appTokenSource struct {
+	ctx            context.Context
+	restURL        string
+	appID          string
+	installationID int64
+	privateKey     any
+}
+
+// newAppTokenSource reads the app's PEM-encoded RSA private key from
+// keyFile. restURL is the base URL of the REST API, such as defaultRESTURL
+// or https://HOST/api/v3 for GitHub Enterprise Server.
+func newAppTokenSource(ctx context.Context, restURL, appID string, installationID int64, keyFile string) (*appTokenSource, error) {
+	pem, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", keyFile, err)
+	}
+	return &appTokenSource{ctx: ctx, restURL: restURL, appID: appID, installationID: installationID, privateKey: key}, nil
+}
+
+// Token exchanges a freshly signed app JWT for an installation token.
+func (s *appTokenSource) Token() (*oauth2.Token, error) {
+	// GitHub rejects JWTs issued in the future, so backdate against clock
+	// drift, and caps their lifetime at ten minutes.
+	now := time.Now()
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{
+		Issuer:    s.appID,
+		IssuedAt:  jwt.NewNumericDate(now.Add(-time.Minute)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(9 * time.Minute)),
+	}).SignedString(s.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("signing GitHub App JWT: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", strings.TrimSuffix(s.restURL, "/"), s.installationID)
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+signed)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	client := http.DefaultClient
+	if c, ok := s.ctx.Value(oauth2.HTTPClient).(*http.Client); ok {
+		client = c
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting installation token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("requesting installation token: %s", resp.Status)
+	}
+	var body struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decoding installation token: %w", err)
+	}
+	return &oauth2.Token{AccessToken: body.Token, Expiry: body.ExpiresAt}, nil
+}
+
+// restURLFor returns the REST API base URL that belongs to the GraphQL
+// endpoint given with --graphql-endpoint: GitHub Enterprise Server serves
+// GraphQL at /api/graphql and REST at /api/v3.
+func restURLFor(graphqlEndpoint string) string {
+	if base, ok := strings.CutSuffix(graphqlEndpoint, "/api/graphql"); ok {
+		return base + "/api/v3"
+	}
+	return defaultRESTURL
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"time"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// generateHCL writes the items as a Terraform locals block, so a module can
+// read them as local.pending_payments. Attribute names follow the snake_case
+// keys of the JSON output and timestamps are RFC 3339 strings.
+func generateHCL(items []ProjectItem, filename string) error {
+	values := make([]cty.Value, len(items))
+	for i, item := range items {
+		values[i] = cty.ObjectVal(map[string]cty.Value{
+			"id":              cty.StringVal(item.ID),
+			"title":           cty.StringVal(item.Title),
+			"url":             cty.StringVal(item.URL),
+			"created_at":      cty.StringVal(item.CreatedAt.Format(time.RFC3339)),
+			"updated_at":      cty.StringVal(item.UpdatedAt.Format(time.RFC3339)),
+			"due_date":        cty.StringVal(item.DueDate),
+			"created_by":      cty.StringVal(item.CreatedBy),
+			"assigned_to":     stringListVal(item.AssignedTo),
+			"labels":          stringListVal(item.Labels),
+			"description":     cty.StringVal(item.Description),
+			"recipient":       cty.StringVal(item.Recipient),
+			"bounty_amount":   cty.StringVal(item.BountyAmount),
+			"bounty_symbol":   cty.StringVal(item.BountySymbol),
+			"org":             cty.StringVal(item.Org),
+			"project_number":  cty.NumberIntVal(int64(item.ProjectNumber)),
+			"status":          cty.StringVal(item.Status),
+			"sprint":          cty.StringVal(item.Sprint),
+			"content_type":    cty.StringVal(item.ContentType),
+			"signature_valid": cty.BoolVal(item.SignatureValid),
+		})
+	}
+	list := cty.EmptyTupleVal
+	if len(values) > 0 {
+		list = cty.TupleVal(values)
+	}
+
+	f := hclwrite.NewEmptyFile()
+	locals := f.Body().AppendNewBlock("locals", nil)
+	locals.Body().SetAttributeValue("pending_payments", list)
+
+	file, err := createOutput(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Abort()
+
+	if _, err := f.WriteTo(file); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func stringListVal(values []string) cty.Value {
+	if len(values) == 0 {
+		return cty.ListValEmpty(cty.String)
+	}
+	list := make([]cty.Value, len(values))
+	for i, v := range values {
+		list[i] = cty.StringVal(v)
+	}
+	return cty.ListVal(list)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+// generateICal writes an iCalendar file with one all-day VEVENT per item on
+// its due date, so payment deadlines can be imported into a calendar.
+// Items without a due date are skipped, and so is the file when none has
+// one.
+func generateICal(items []ProjectItem, filename string) error {
+	cal := ical.NewCalendar()
+	cal.Props.SetText(ical.PropVersion, "2.0")
+	cal.Props.SetText(ical.PropProductID, "-//NautilusOSS//buidl-tools//EN")
+
+	now := time.Now()
+	for _, item := range items {
+		if item.DueDate == "" {
+			continue
+		}
+		due, err := parseDueDate(item.DueDate)
+		if err != nil {
+			slog.Warn("Skipping item in iCalendar output", "url", item.URL, "err", err)
+			continue
+		}
+
+		event := ical.NewEvent()
+		event.Props.SetText(ical.PropUID, item.ID)
+		event.Props.SetDateTime(ical.PropDateTimeStamp, now.UTC())
+		event.Props.SetDate(ical.PropDateTimeStart, due)
+		event.Props.SetDate(ical.PropDateTimeEnd, due.AddDate(0, 0, 1))
+		event.Props.SetText(ical.PropSummary, item.Title)
+		if u, err := url.Parse(item.URL); err == nil && item.URL != "" {
+			event.Props.SetURI(ical.PropURL, u)
+		}
+		event.Props.SetText(ical.PropDescription, strings.TrimSpace(item.BountyAmount+" "+item.BountySymbol))
+		cal.Children = append(cal.Children, event.Component)
+	}
+
+	// RFC 5545 requires at least one component in a calendar.
+	if len(cal.Children) == 0 {
+		return fmt.Errorf("no items have a due date: %w", errSkipOutput)
+	}
+
+	file, err := createOutput(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Abort()
+
+	if err := ical.NewEncoder(file).Encode(cal); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// parseDueDate accepts a due date as either YYYY-MM-DD or RFC 3339.
+func parseDueDate(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateICalSkipsFileWithoutDueDates(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "due.ics")
+	err := generateICal([]ProjectItem{{ID: "1", Title: "No due date"}}, filename)
+	if !errors.Is(err, errSkipOutput) {
+		t.Errorf("generateICal = %v, want errSkipOutput", err)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+)
+
+// generateRecipientMap writes a single JSON object mapping each recipient to
+// their summed bounty, e.g. {"0xabc...": 1000, "0xdef...": 500}. This is the
+// shape smart contract deploy scripts need for (address[], uint256[])
+// constructor arguments.
+func generateRecipientMap(items []ProjectItem, filename string) error {
+	file, err := createOutput(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Abort()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(bountyByRecipient(items)); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// generateJSON writes the items as a pretty-printed JSON array. Unlike the
+// CSV, assignees and labels stay proper arrays.
+func generateJSON(items []ProjectItem, filename string) error {
+	file, err := createOutput(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Abort()
+
+	if items == nil {
+		items = []ProjectItem{}
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(items); err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"time"
+
+	"github.com/google/jsonapi"
+)
+
+// jsonAPIItem is a ProjectItem as a JSON:API "project-items" resource.
+// Assignees and labels become relationships to "users" and "labels"
+// resources, which are listed in the document's included array.
+type jsonAPIItem struct {
+	ID             string          `jsonapi:"primary,project-items"`
+	Title          string          `jsonapi:"attr,title"`
+	URL            string          `jsonapi:"attr,url"`
+	CreatedAt      time.Time       `jsonapi:"attr,created_at,iso8601"`
+	UpdatedAt      time.Time       `jsonapi:"attr,updated_at,iso8601"`
+	DueDate        string          `jsonapi:"attr,due_date"`
+	CreatedBy      string          `jsonapi:"attr,created_by"`
+	Description    string          `jsonapi:"attr,description"`
+	Recipient      string          `jsonapi:"attr,recipient"`
+	BountyAmount   string          `jsonapi:"attr,bounty_amount"`
+	BountySymbol   string          `jsonapi:"attr,bounty_symbol"`
+	Org            string          `jsonapi:"attr,org"`
+	ProjectNumber  int             `jsonapi:"attr,project_number"`
+	Status         string          `jsonapi:"attr,status"`
+	Sprint         string          `jsonapi:"attr,sprint"`
+	ContentType    string          `jsonapi:"attr,content_type"`
+	SignatureValid bool            `jsonapi:"attr,signature_valid"`
+	Assignees      []*jsonAPIUser  `jsonapi:"relation,assignees"`
+	Labels         []*jsonAPILabel `jsonapi:"relation,labels"`
+}
+
+type jsonAPIUser struct {
+	Login string `jsonapi:"primary,users"`
+}
+
+type jsonAPILabel struct {
+	Name string `jsonapi:"primary,labels"`
+}
+
+// generateJSONAPI writes the items as a JSON:API (jsonapi.org) document.
+func generateJSONAPI(items []ProjectItem, filename string) error {
+	resources := make([]*jsonAPIItem, len(items))
+	for i, item := range items {
+		resource := &jsonAPIItem{
+			ID:             item.ID,
+			Title:          item.Title,
+			URL:            item.URL,
+			CreatedAt:      item.CreatedAt,
+			UpdatedAt:      item.UpdatedAt,
+			DueDate:        item.DueDate,
+			CreatedBy:      item.CreatedBy,
+			Description:    item.Description,
+			Recipient:      item.Recipient,
+			BountyAmount:   item.BountyAmount,
+			BountySymbol:   item.BountySymbol,
+			Org:            item.Org,
+			ProjectNumber:  item.ProjectNumber,
+			Status:         item.Status,
+			Sprint:         item.Sprint,
+			ContentType:    item.ContentType,
+			SignatureValid: item.SignatureValid,
+		}
+		for _, login := range item.AssignedTo {
+			resource.Assignees = append(resource.Assignees, &jsonAPIUser{Login: login})
+		}
+		for _, name := range item.Labels {
+			resource.Labels = append(resource.Labels, &jsonAPILabel{Name: name})
+		}
+		resources[i] = resource
+	}
+
+	file, err := createOutput(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Abort()
+
+	if err := jsonapi.MarshalPayload(file, resources); err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// loadLabelPalette reads the --label-palette file: a JSON object mapping
+// label names to their approved hex colors, e.g. {"bounty": "#0e8a16"}.
+// Names are matched case-insensitively and colors may omit the leading '#'.
+func loadLabelPalette(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	palette := make(map[string]string, len(raw))
+	for name, color := range raw {
+		c := normalizeColor(color)
+		if len(c) != 6 || strings.Trim(c, "0123456789abcdef") != "" {
+			return nil, fmt.Errorf("%s: label %q: %q is not a hex color", path, name, color)
+		}
+		palette[strings.ToLower(name)] = c
+	}
+	return palette, nil
+}
+
+// normalizeColor lowercases a hex color and strips its '#', the form GitHub
+// reports label colors in.
+func normalizeColor(color string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(color), "#"))
+}
+
+// checkLabelColor reports labels in palette whose color on GitHub differs
+// from the approved one. Labels missing from the palette are not checked.
+func checkLabelColor(items []ProjectItem, palette map[string]string) []finding {
+	var findings []finding
+	for _, item := range items {
+		labels := make([]string, 0, len(item.LabelColors))
+		for label := range item.LabelColors {
+			labels = append(labels, label)
+		}
+		sort.Strings(labels)
+		for _, label := range labels {
+			want, ok := palette[strings.ToLower(label)]
+			if got := normalizeColor(item.LabelColors[label]); ok && got != want {
+				findings = append(findings, finding{
+					check:   "label-color",
+					message: fmt.Sprintf("%s: label %q has color #%s, the palette approves #%s", item.URL, label, got, want),
+				})
+			}
+		}
+	}
+	return findings
+}
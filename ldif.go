@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// defaultLDIFBaseDN is the parent entry of the ldif output's entries.
+const defaultLDIFBaseDN = "ou=payments,dc=example,dc=com"
+
+// ldifAttribute is one attribute of an exported entry. An attribute with
+// several values, such as assignedTo, is written once per value.
+type ldifAttribute struct {
+	name   string
+	values []string
+}
+
+// generateLDIF writes the items as an LDIF (RFC 2849) file with one
+// cn={ID},baseDN entry per item, for HR and payroll systems that
+// synchronize through an LDAP directory. Attribute names are the
+// ProjectItem field names in lowerCamelCase; entries use the
+// extensibleObject class so any directory schema accepts them.
+func generateLDIF(items []ProjectItem, filename, baseDN string) error {
+	file, err := createOutput(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Abort()
+
+	w := bufio.NewWriter(file)
+	fmt.Fprintln(w, "version: 1")
+	for _, item := range items {
+		attributes := []ldifAttribute{
+			{"objectClass", []string{"top", "extensibleObject"}},
+			{"cn", []string{item.ID}},
+			{"id", []string{item.ID}},
+			{"title", []string{item.Title}},
+			{"url", []string{item.URL}},
+			{"createdAt", []string{item.CreatedAt.Format(time.RFC3339)}},
+			{"updatedAt", []string{item.UpdatedAt.Format(time.RFC3339)}},
+			{"dueDate", []string{item.DueDate}},
+			{"createdBy", []string{item.CreatedBy}},
+			{"assignedTo", item.AssignedTo},
+			{"labels", item.Labels},
+			{"description", []string{item.Description}},
+			{"recipient", []string{item.Recipient}},
+			{"bountyAmount", []string{item.BountyAmount}},
+			{"bountySymbol", []string{item.BountySymbol}},
+			{"org", []string{item.Org}},
+			{"projectNumber", []string{strconv.Itoa(item.ProjectNumber)}},
+			{"status", []string{item.Status}},
+			{"sprint", []string{item.Sprint}},
+			{"contentType", []string{item.ContentType}},
+			{"signatureValid", []string{strings.ToUpper(strconv.FormatBool(item.SignatureValid))}},
+		}
+
+		fmt.Fprintln(w)
+		writeLDIFLine(w, "dn", "cn="+escapeDNValue(item.ID)+","+baseDN)
+		for _, attr := range attributes {
+			for _, value := range attr.values {
+				// LDAP has no empty values; a missing field is left out.
+				if value != "" {
+					writeLDIFLine(w, attr.name, value)
+				}
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// writeLDIFLine writes "name: value", switching to the base64 "name:: value"
+// form for values that are not safe to write as-is.
+func writeLDIFLine(w *bufio.Writer, name, value string) {
+	if ldifSafe(value) {
+		fmt.Fprintf(w, "%s: %s\n", name, value)
+		return
+	}
+	fmt.Fprintf(w, "%s:: %s\n", name, base64.StdEncoding.EncodeToString([]byte(value)))
+}
+
+// ldifSafe reports whether value is a SAFE-STRING in the sense of RFC 2849:
+// printable ASCII without line breaks that does not start with a space,
+// colon or '<', and does not end with a space.
+func ldifSafe(value string) bool {
+	if strings.HasPrefix(value, " ") || strings.HasPrefix(value, ":") || strings.HasPrefix(value, "<") || strings.HasSuffix(value, " ") {
+		return false
+	}
+	for _, r := range value {
+		if r >= utf8.RuneSelf || r == 0 || r == '\n' || r == '\r' {
+			return false
+		}
+	}
+	return true
+}
+
+// escapeDNValue escapes value for use as an attribute value in a DN, as
+// described in RFC 4514.
+func escapeDNValue(value string) string {
+	var b strings.Builder
+	for i, r := range value {
+		switch {
+		case strings.ContainsRune(`,+"\<>;=`, r),
+			r == '#' && i == 0,
+			r == ' ' && (i == 0 || i == len(value)-1):
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -2,316 +2,869 @@ package main
 
 import (
 	"context"
-	"encoding/csv"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"syscall"
+	"text/template"
 	"time"
 
 	"github.com/shurcooL/githubv4"
+	"github.com/zricethezav/gitleaks/v8/detect"
 	"golang.org/x/oauth2"
 )
 
 type ProjectItem struct {
-	ID           string
-	Title        string
-	URL          string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	DueDate      string
-	AssignedTo   []string
-	Labels       []string
-	Description  string
-	Recipient    string
-	BountyAmount string
-	BountySymbol string
+	ID           string    `json:"id" toml:"id" xml:"ID"`
+	Title        string    `json:"title" toml:"title" xml:"Title"`
+	URL          string    `json:"url" toml:"url" xml:"URL"`
+	CreatedAt    time.Time `json:"created_at" toml:"created_at" xml:"CreatedAt"`
+	UpdatedAt    time.Time `json:"updated_at" toml:"updated_at" xml:"UpdatedAt"`
+	DueDate      string    `json:"due_date" toml:"due_date" xml:"DueDate"`
+	CreatedBy    string    `json:"created_by" toml:"created_by" xml:"CreatedBy"`
+	AssignedTo   []string  `json:"assigned_to" toml:"assigned_to" xml:"AssignedTo>Login"`
+	Labels       []string  `json:"labels" toml:"labels" xml:"Labels>Label"`
+	Description  string    `json:"description" toml:"description" xml:"Description"`
+	Recipient    string    `json:"recipient" toml:"recipient" xml:"Recipient"`
+	BountyAmount string    `json:"bounty_amount" toml:"bounty_amount" xml:"BountyAmount"`
+	BountySymbol string    `json:"bounty_symbol" toml:"bounty_symbol" xml:"BountySymbol"`
+	Org          string    `json:"org" toml:"org" xml:"Org"`
+	Status       string    `json:"status" toml:"status" xml:"Status"`
+	Sprint       string    `json:"sprint" toml:"sprint" xml:"Sprint"`
+	ContentType  string    `json:"content_type" toml:"content_type" xml:"ContentType"` // "issue", "pull_request" or "draft_issue"
+	// ProjectNumber is the number of the project in Org the item was
+	// exported from.
+	ProjectNumber int `json:"project_number" toml:"project_number" xml:"ProjectNumber"`
+	// LabelColors maps each of Labels to its hex color, for
+	// --check-label-color. It is not exported.
+	LabelColors map[string]string `json:"-" toml:"-" xml:"-"`
+	// LinkedPullRequests lists the URLs of pull requests that cross-reference
+	// an issue, for --check-linked-pr. It is not exported.
+	LinkedPullRequests []string `json:"-" toml:"-" xml:"-"`
+	// SignatureValid is set by --verify-signatures.
+	SignatureValid bool `json:"signature_valid" toml:"signature_valid" xml:"SignatureValid"`
+}
+
+// statusOut receives progress messages. It is switched to stderr when items
+// are streamed to stdout so the stream stays machine-readable.
+var statusOut io.Writer = os.Stdout
+
+// options holds the command-line configuration for a run.
+type options struct {
+	orgs                 stringList
+	noPagination         bool
+	firstN               int
+	formats              []string
+	githubDebug          bool
+	expandMultiValue     bool
+	maxItemAgeDays       int
+	checkTotal           float64
+	checkTotalTolerance  float64
+	secretScanning       bool
+	watch                time.Duration
+	watchOutputDir       string
+	preview              bool
+	repoFilters          stringList
+	concurrentExports    bool
+	checkWalletBalance   string
+	ethereumRPC          string
+	tokenAddress         string
+	graphqlEndpoint      string
+	checkNoDuplicateURLs bool
+	recent               int
+	strict               bool
+	checkLabelRequired   bool
+	checkProjectStatus   bool
+	summaryTableFormat   string
+	minDescriptionLength int
+	sprintField          string
+	checkOrgMembership   bool
+	chainID              int64
+	checkTokenConfig     bool
+	sinceLastRun         bool
+	streamItems          bool
+	verifySignatures     bool
+	titleTemplate        *template.Template
+	ignoreParseErrors    bool
+	noTLSVerify          bool
+	recentActivityDays   int
+	sepaDebtorIBAN       string
+	sepaDebtorName       string
+	checkIBAN            bool
+	requiredFields       stringList
+	maxProjectAgeHours   int
+	checkURLReachable    bool
+	concurrency          int
+	summaryMaxRecent     int
+	statuses             stringList
+	checkIssuer          bool
+	fields               stringList
+	watchAlertThreshold  int
+	webhookURL           string
+	projectNumber        int
+	outputDir            string
+	githubToken          string
+	verifyCSVHash        string
+	ownerType            string
+	updatedAfter         time.Time
+	updatedBefore        time.Time
+	projectID            string
+	checkBountyInteger   bool
+	normalizeDecimals    stringList
+	assigneesFormat      string
+	gitleaks             *detect.Detector
+	ldifBaseDN           string
+	sqliteMode           string
+	cacheDir             string
+	cacheTTL             time.Duration
+	noCache              bool
+	locale               string
+	fromCache            bool
+	cacheDB              string
+	cacheMaxAge          time.Duration
+	projects             []projectRef
+	exclusiveLabels      stringList
+	rateLimitThreshold   int
+	decodeCSVBase64      string
+	appID                string
+	installationID       int64
+	privateKeyFile       string
+	checkSelfAssignment  bool
+	githubGraphQLURL     string
+	labelPalette         map[string]string
+	retryOnEmpty         bool
+	retryCount           int
+	retryDelay           time.Duration
+	csvMode              string
+	checkLinkedPR        bool
+	bountySymbol         string
+	bountyFieldName      string
+	dueDateField         string
+	recipientCap         float64
+	capPeriod            string
+}
+
+func parseFlags() *options {
+	opts := &options{}
+	configPath := flag.String("config", defaultConfigFile, "YAML configuration file; command-line flags override its settings")
+	flag.StringVar(&opts.ownerType, "owner-type", ownerTypeOrg, "whether --org names an organization (org) or a personal account (user) that owns the project")
+	flag.IntVar(&opts.projectNumber, "project-number", 2, "number of the organization's GitHub project to export from")
+	projectNumberEnv := flag.String("project-number-env", "", "read --project-number from this environment variable, e.g. PROJECT_NUMBER")
+	flag.Var(&opts.orgs, "org", "GitHub organization to export from; repeat or comma-separate for several (default NautilusOSS)")
+	var orgProjects stringList
+	flag.Var(&orgProjects, "orgs", "projects to export from and merge as ORG:PROJECT_NUMBER, e.g. NautilusOSS:2,AnotherOrg:5; replaces --org and --project-number")
+	flag.StringVar(&opts.appID, "app-id", "", "authenticate as the GitHub App with this ID when GITHUB_TOKEN is not set; needs --installation-id and --private-key-file")
+	flag.Int64Var(&opts.installationID, "installation-id", 0, "installation of the --app-id GitHub App on the organization")
+	flag.StringVar(&opts.privateKeyFile, "private-key-file", "", "PEM private key of the --app-id GitHub App")
+	flag.StringVar(&opts.projectID, "project-id", "", "node ID of the project (PVT_...), skipping the lookup by --org and --project-number")
+	flag.Var(&opts.statuses, "status", "export items with this project status; repeat or comma-separate for several, or pass \"\" for all (default \"Pending Payment\")")
+	flag.Var(&opts.repoFilters, "repo-filter", "only export items from this OWNER/REPO; repeat or comma-separate to allow several")
+	flag.BoolVar(&opts.sinceLastRun, "since-last-run", false, "only export items updated since the last successful run (tracked in ~/.buidl-tools/state.json)")
+	updatedAfterFlag := flag.String("updated-after", "", "only export items updated at or after this RFC 3339 time or YYYY-MM-DD date")
+	updatedBeforeFlag := flag.String("updated-before", "", "only export items updated at or before this RFC 3339 time or YYYY-MM-DD date (the whole day counts)")
+	flag.IntVar(&opts.recent, "recent", 0, "only export the N most recently updated items (0 exports all)")
+	flag.StringVar(&opts.dueDateField, "due-date-field", "Due Date", "name of the project's date field to export as the Due Date column")
+	flag.StringVar(&opts.sprintField, "sprint-field", "", "name of the project's iteration field to export as the Sprint column")
+	flag.BoolVar(&opts.noPagination, "no-pagination", false, "fetch project items with a single query instead of following cursors")
+	flag.IntVar(&opts.firstN, "first-n", 100, "number of project items to request per query (max 100)")
+	flag.StringVar(&opts.bountySymbol, "bounty-symbol", "BUIDL", "token symbol that marks a text field value as the bounty, e.g. USDC; also the symbol of number-field bounties")
+	flag.StringVar(&opts.bountyFieldName, "bounty-field-name", "", "name of the project field holding the bounty, instead of detecting it by --bounty-symbol")
+	flag.BoolVar(&opts.ignoreParseErrors, "ignore-parse-errors", false, "skip items with a malformed bounty amount or due date instead of failing")
+	flag.StringVar(&opts.githubGraphQLURL, "github-graphql-url", "", "GraphQL API URL of a GitHub Enterprise Server instance, e.g. https://github.example.com/api/graphql")
+	flag.StringVar(&opts.graphqlEndpoint, "graphql-endpoint", "", "send GraphQL queries to this URL instead of api.github.com (e.g. a mock server in tests)")
+	flag.BoolVar(&dryRun, "dry-run", false, "print the output files to stdout, each after a \"--- <filename> ---\" line, instead of writing them")
+	flag.StringVar(&opts.cacheDir, "cache-dir", "", "cache GitHub API responses as JSON files in this directory, for development")
+	flag.DurationVar(&opts.cacheTTL, "cache-ttl", 5*time.Minute, "how long a --cache-dir response is reused")
+	flag.BoolVar(&opts.fromCache, "from-cache", false, "read the items from --cache-db instead of GitHub, e.g. when the API is down or out of quota")
+	flag.StringVar(&opts.cacheDB, "cache-db", "pending_payment_tasks.db", "database written by --format sqlite that --from-cache reads and csv-delta compares with and updates")
+	flag.DurationVar(&opts.cacheMaxAge, "cache-max-age", 0, "with --from-cache, fail if the cached export is older than this (0 accepts any age)")
+	flag.BoolVar(&opts.noCache, "no-cache", false, "ignore --cache-dir and always query GitHub")
+	flag.IntVar(&opts.rateLimitThreshold, "rate-limit-threshold", 100, "warn when fewer GitHub rate-limit points than this remain")
+	flag.BoolVar(&opts.retryOnEmpty, "retry-on-empty", false, "fetch the items again when none match, e.g. when a status change has not reached the API yet")
+	flag.IntVar(&opts.retryCount, "retry-count", 3, "times --retry-on-empty fetches again before exporting an empty result")
+	flag.DurationVar(&opts.retryDelay, "retry-delay", 30*time.Second, "wait between --retry-on-empty attempts")
+	flag.IntVar(&maxQueryRetries, "max-retries", 3, "times a GitHub query is retried after a 502, 503 or timeout, with exponential backoff")
+	flag.BoolVar(&opts.noTLSVerify, "no-tls-verify", false, "skip TLS certificate verification of GitHub requests (development only)")
+	logLevel := flag.String("log-level", "info", "minimum level of log messages on stderr: debug, info, warn or error")
+	logFormat := flag.String("log-format", logFormatText, "format of log messages on stderr: text or json (one object per line)")
+	errorLogPath := flag.String("error-log", "", "also append warnings and errors to this file as JSON lines")
+	flag.BoolVar(&opts.githubDebug, "github-debug", false, "log DNS, connect, TLS and first-byte timings of GitHub requests to stderr")
+	flag.StringVar(&opts.locale, "locale", "en_US", "locale of the thousand separators and decimal mark written by --format csv-money, e.g. de_DE")
+	flag.StringVar(&opts.assigneesFormat, "assignees-format", "join", "how CSV exports list assignees: join (one semicolon-separated column) or expand (Assignee 1, Assignee 2, ... columns)")
+	flag.BoolVar(&opts.expandMultiValue, "expand-multi-value", false, "write one CSV row per assignee with an assignee_index column")
+	flag.IntVar(&opts.maxItemAgeDays, "max-item-age-days", 0, "warn about items created more than this many days ago (0 disables)")
+	flag.IntVar(&opts.recentActivityDays, "check-recent-activity", 0, "warn if no item has been updated in this many days (0 disables)")
+	flag.Float64Var(&opts.checkTotal, "check-total", 0, "warn if the total bounty differs from this many --bounty-symbol tokens (0 disables)")
+	flag.Float64Var(&opts.checkTotalTolerance, "check-total-tolerance", 0, "percentage the total may differ from --check-total (e.g. 5 allows ±5%)")
+	flag.BoolVar(&opts.strict, "strict", false, "treat check findings as errors and fail the run before writing output")
+	flag.BoolVar(&opts.checkProjectStatus, "check-github-project-status", false, "abort if the GitHub project is closed")
+	flag.IntVar(&opts.maxProjectAgeHours, "max-project-age-hours", 0, "abort if the GitHub project was last modified more than this many hours ago (0 disables)")
+	flag.Var(&opts.requiredFields, "check-field-exists", "abort if the project has no field with this name; repeat or comma-separate for several")
+	flag.BoolVar(&opts.checkLabelRequired, "check-label-required", false, "warn about items without any label")
+	flag.BoolVar(&opts.checkSelfAssignment, "check-no-self-assignment", false, "warn about items whose author is also their only assignee")
+	flag.Var(&opts.exclusiveLabels, "check-labels-exclusive", "fail if an item has several labels starting with one of these comma-separated prefixes, e.g. type:,priority:")
+	flag.IntVar(&opts.minDescriptionLength, "min-description-length", 0, "warn about items whose description is shorter than this many characters (0 disables)")
+	flag.BoolVar(&opts.checkBountyInteger, "check-bounty-integer", false, "warn about bounty amounts that are not whole numbers")
+	flag.BoolVar(&opts.checkLinkedPR, "check-linked-pr", false, "warn about issues that no pull request cross-references as proof of work")
+	flag.BoolVar(&opts.checkIssuer, "check-issuer", false, "warn about items whose issue is not in a repository of the project's organization")
+	flag.BoolVar(&opts.checkIBAN, "check-iban", false, "warn about items whose recipient is not a valid IBAN")
+	flag.BoolVar(&opts.checkNoDuplicateURLs, "check-no-duplicate-urls", false, "warn about issues that appear in the project more than once")
+	flag.BoolVar(&opts.checkURLReachable, "check-url-reachable", false, "warn about item URLs that do not answer a HEAD request with 200 OK")
+	flag.IntVar(&opts.concurrency, "concurrency", 8, "maximum number of parallel requests made by --check-url-reachable")
+	flag.BoolVar(&opts.checkOrgMembership, "check-org-membership", false, "warn about assignees who are not members of the item's organization")
+	flag.StringVar(&opts.checkWalletBalance, "check-wallet-balance", "", "warn if this payer address holds less of --token-address than the total bounty")
+	flag.StringVar(&opts.ethereumRPC, "ethereum-rpc", "", "Ethereum JSON-RPC endpoint used by --check-wallet-balance")
+	flag.StringVar(&opts.tokenAddress, "token-address", "", "ERC-20 contract address of the bounty token")
+	flag.Int64Var(&opts.chainID, "chain-id", 0, "EVM chain ID the bounty token is deployed on (1=mainnet, 10=optimism, 137=polygon, 42161=arbitrum)")
+	flag.BoolVar(&opts.checkTokenConfig, "check-payment-token-config", false, "validate --token-address and --chain-id (and --ethereum-rpc when set) before running")
+	flag.Var(&opts.normalizeDecimals, "normalize-decimals", "token decimals for csv-normalized as SYMBOL:DECIMALS, e.g. BUIDL:18,USDC:6")
+	flag.StringVar(&opts.sepaDebtorIBAN, "sepa-debtor-iban", "", "IBAN the sepa-xml transfers are paid from")
+	flag.StringVar(&opts.sepaDebtorName, "sepa-debtor-name", "", "account holder name of --sepa-debtor-iban")
+	flag.StringVar(&opts.csvMode, "csv-mode", csvOverwrite, "whether --format csv replaces the file (overwrite) or adds the items it does not hold yet (append)")
+	flag.StringVar(&opts.sqliteMode, "sqlite-mode", sqliteOverwrite, "whether --format sqlite replaces the project_items table (overwrite) or adds to it (append)")
+	flag.StringVar(&opts.ldifBaseDN, "ldif-base-dn", defaultLDIFBaseDN, "DN under which --format ldif places the item entries")
+	flag.BoolVar(&opts.verifySignatures, "verify-signatures", false, "verify PGP signatures in item front matter against the first assignee's GitHub GPG keys")
+	checkGitleaks := flag.Bool("check-gitleaks", false, "warn about secrets in item titles and descriptions found by the gitleaks rules")
+	checkAll := flag.Bool("check-all", false, "enable every check that takes no value: --check-github-project-status, --check-label-required, --check-no-self-assignment, --check-bounty-integer, --check-linked-pr, --check-issuer, --check-no-duplicate-urls, --check-url-reachable, --check-org-membership and --check-gitleaks")
+	checkLabelColor := flag.Bool("check-label-color", false, "warn about labels whose color differs from the one approved in --label-palette")
+	labelPalette := flag.String("label-palette", "", "JSON file mapping label names to approved hex colors, for --check-label-color")
+	gitleaksConfig := flag.String("gitleaks-config", "", "gitleaks TOML config for --check-gitleaks (default the built-in gitleaks rules)")
+	flag.BoolVar(&opts.secretScanning, "secret-scanning", false, "redact tokens and private keys found in item titles and descriptions before export")
+	titleTemplate := flag.String("item-title-template", "", "Go template for exported titles, e.g. '[BUIDL-{{.Index}}] {{.Title}}' (fields: Index, Title, BountyAmount, Recipient)")
+	flag.BoolVar(&opts.preview, "preview", false, "show the items in a table and ask for confirmation before exporting (ignored when stdout is not a terminal)")
+	flag.DurationVar(&opts.watch, "watch", 0, "re-run the export at this interval until interrupted (e.g. 10m)")
+	flag.StringVar(&opts.watchOutputDir, "watch-output-dir", "", "in --watch mode, write each cycle's files to this directory with a UTC timestamp in the name")
+	flag.IntVar(&opts.watchAlertThreshold, "watch-alert-threshold", 0, "in --watch mode, alert when the item count changes by more than this between cycles (0 disables)")
+	flag.StringVar(&opts.webhookURL, "webhook-url", "", "URL that --watch-alert-threshold alerts are POSTed to as JSON")
+	flag.StringVar(&opts.decodeCSVBase64, "decode-csv-base64", "", "write this csv-base64 export to stdout with its base64 columns decoded and exit, instead of exporting")
+	flag.StringVar(&opts.verifyCSVHash, "verify-csv-hash", "", "check the row_hash values of this csv-hash export and exit, instead of exporting")
+	flag.StringVar(&opts.outputDir, "output-dir", "", "directory the output files are written to (default the current directory)")
+	flag.BoolVar(&opts.concurrentExports, "concurrent-exports", false, "write the output files in parallel and report all failures together")
+	flag.StringVar(&opts.summaryTableFormat, "summary-table-format", summaryTablePlain, "layout of the summary's Items by Recipient section: plain, markdown-table or ascii-table")
+	flag.IntVar(&opts.summaryMaxRecent, "summary-max-recent", 5, "number of items listed in the summary's Recent Activity section (-1 lists all)")
+	flag.Float64Var(&opts.recipientCap, "recipient-cap", 0, "warn about recipients paid more than this many --bounty-symbol tokens per --cap-period and list them in the summary (0 disables)")
+	flag.StringVar(&opts.capPeriod, "cap-period", capPeriodRun, "period --recipient-cap applies to: run (all exported items), month or year (items created this calendar month or year)")
+	flag.Var(&opts.fields, "fields", "columns of the markdown-table format, by CSV header name, e.g. Title,Recipient,\"Bounty Amount\" (default all CSV columns)")
+	outputFormat := flag.String("output-format", "", "shorthand for --format: csv, json, markdown or all (all three)")
+	format := flag.String("format", "csv", "comma-separated list of output formats: "+strings.Join(formatNames(), ", "))
+	flag.Parse()
+
+	// --github-debug output is logged at debug level, so it implies
+	// --log-level debug unless another level was asked for.
+	if opts.githubDebug && !isFlagSet("log-level") {
+		*logLevel = "debug"
+	}
+	level, err := parseLogLevel(*logLevel)
+	if err != nil {
+		fatal("Unknown --log-level (available: debug, info, warn, error)", "log_level", *logLevel)
+	}
+	if *logFormat != logFormatText && *logFormat != logFormatJSON {
+		fatal("Unknown --log-format (available: text, json)", "log_format", *logFormat)
+	}
+	handler := newLogHandler(os.Stderr, *logFormat, level)
+	if *errorLogPath != "" {
+		file, err := os.OpenFile(*errorLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			fatal("Error opening --error-log", "file", *errorLogPath, "err", err)
+		}
+		handler = teeHandler{handler, newErrorLogHandler(file)}
+	}
+	slog.SetDefault(slog.New(handler))
+
+	cfg, err := loadConfig(*configPath, isFlagSet("config"))
+	if err != nil {
+		fatal("Error reading config file", "file", *configPath, "err", err)
+	}
+	opts.githubToken = cfg.GitHubToken
+	if len(opts.orgs) > 0 && len(orgProjects) > 0 {
+		fatal("--orgs already names each project's organization; do not combine it with --org")
+	}
+	if len(opts.orgs) == 0 {
+		opts.orgs = splitList(cfg.Org)
+	}
+	if len(opts.orgs) == 0 {
+		opts.orgs = stringList{"NautilusOSS"}
+	}
+	if !isFlagSet("project-number") && cfg.ProjectNumber != 0 {
+		opts.projectNumber = cfg.ProjectNumber
+	}
+	if *projectNumberEnv != "" {
+		if isFlagSet("project-number") {
+			fatal("--project-number and --project-number-env cannot be used together")
+		}
+		value, ok := os.LookupEnv(*projectNumberEnv)
+		if !ok {
+			fatal("The environment variable named by --project-number-env is not set", "project_number_env", *projectNumberEnv)
+		}
+		if opts.projectNumber, err = strconv.Atoi(strings.TrimSpace(value)); err != nil {
+			fatal("The environment variable named by --project-number-env is not a number", "project_number_env", *projectNumberEnv, "value", value)
+		}
+	}
+	if !isFlagSet("status") {
+		opts.statuses = stringList{"Pending Payment"}
+		if cfg.StatusFilter != nil {
+			opts.statuses = cfg.StatusFilter
+		}
+	}
+	if !isFlagSet("output-dir") {
+		opts.outputDir = cfg.OutputDir
+	}
+	if !isFlagSet("format") && *outputFormat == "" && cfg.OutputFormat != "" {
+		*format = cfg.OutputFormat
+	}
+	if opts.githubGraphQLURL != "" {
+		if opts.graphqlEndpoint != "" {
+			fatal("--github-graphql-url and --graphql-endpoint cannot be used together")
+		}
+		u, err := url.Parse(opts.githubGraphQLURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" || !strings.HasSuffix(u.Path, "/graphql") {
+			fatal("--github-graphql-url must be an https URL ending in /graphql", "github_graphql_url", opts.githubGraphQLURL)
+		}
+		// From here on the instance is queried like any other endpoint,
+		// including the REST calls made for --app-id.
+		opts.graphqlEndpoint = opts.githubGraphQLURL
+	}
+	if opts.graphqlEndpoint != "" {
+		u, err := url.Parse(opts.graphqlEndpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fatal("--graphql-endpoint must be an http(s) URL", "graphql_endpoint", opts.graphqlEndpoint)
+		}
+	}
+	if appFlags := []bool{opts.appID != "", opts.installationID != 0, opts.privateKeyFile != ""}; slices.Contains(appFlags, true) && slices.Contains(appFlags, false) {
+		fatal("--app-id, --installation-id and --private-key-file must be given together")
+	}
+	if strings.TrimSpace(opts.bountySymbol) == "" {
+		fatal("--bounty-symbol must not be empty")
+	}
+	if opts.firstN < 1 || opts.firstN > 100 {
+		fatal("--first-n must be between 1 and 100", "first_n", opts.firstN)
+	}
+	if opts.cacheMaxAge < 0 {
+		fatal("--cache-max-age must not be negative")
+	}
+	if opts.cacheTTL <= 0 {
+		fatal("--cache-ttl must be positive")
+	}
+	if opts.concurrency < 1 {
+		fatal("--concurrency must be at least 1")
+	}
+	if opts.ownerType != ownerTypeOrg && opts.ownerType != ownerTypeUser {
+		fatal("Unknown --owner-type (available: org, user)", "owner_type", opts.ownerType)
+	}
+	if opts.projectID != "" {
+		if !strings.HasPrefix(opts.projectID, "PVT_") {
+			fatal("--project-id must be a Projects v2 node ID starting with PVT_", "project_id", opts.projectID)
+		}
+		if len(opts.orgs) > 1 || len(orgProjects) > 1 {
+			fatal("--project-id identifies a single project and cannot be combined with several --org or --orgs values")
+		}
+		if opts.checkProjectStatus || opts.maxProjectAgeHours > 0 {
+			fatal("--check-github-project-status and --max-project-age-hours need the project lookup that --project-id skips")
+		}
+	}
+	if opts.projectNumber < 1 {
+		fatal("--project-number must be positive", "project_number", opts.projectNumber)
+	}
+	if len(orgProjects) > 0 {
+		if opts.projects, err = parseProjectRefs(orgProjects); err != nil {
+			fatal("Invalid --orgs", "err", err)
+		}
+	} else {
+		for _, org := range opts.orgs {
+			opts.projects = append(opts.projects, projectRef{org: org, number: opts.projectNumber})
+		}
+	}
+	if *updatedAfterFlag != "" {
+		if opts.updatedAfter, err = parseTimeBound(*updatedAfterFlag, false); err != nil {
+			fatal("Invalid --updated-after: use RFC 3339 or YYYY-MM-DD", "updated_after", *updatedAfterFlag)
+		}
+	}
+	if *updatedBeforeFlag != "" {
+		if opts.updatedBefore, err = parseTimeBound(*updatedBeforeFlag, true); err != nil {
+			fatal("Invalid --updated-before: use RFC 3339 or YYYY-MM-DD", "updated_before", *updatedBeforeFlag)
+		}
+	}
+	if !opts.updatedAfter.IsZero() && !opts.updatedBefore.IsZero() && opts.updatedBefore.Before(opts.updatedAfter) {
+		fatal("--updated-before must not be earlier than --updated-after")
+	}
+	if maxQueryRetries < 0 {
+		fatal("--max-retries must not be negative")
+	}
+	if (isFlagSet("retry-count") || isFlagSet("retry-delay")) && !opts.retryOnEmpty {
+		fatal("--retry-count and --retry-delay require --retry-on-empty")
+	}
+	if opts.retryCount < 1 || opts.retryDelay < 0 {
+		fatal("--retry-count must be at least 1 and --retry-delay must not be negative", "retry_count", opts.retryCount, "retry_delay", opts.retryDelay)
+	}
+	if opts.recent < 0 {
+		fatal("--recent must not be negative")
+	}
+	if opts.checkTotalTolerance < 0 {
+		fatal("--check-total-tolerance must not be negative")
+	}
+	if opts.checkWalletBalance != "" && (opts.ethereumRPC == "" || opts.tokenAddress == "") {
+		fatal("--check-wallet-balance requires --ethereum-rpc and --token-address")
+	}
+	if _, err := selectColumns(opts.fields); err != nil {
+		fatal("Invalid --fields", "err", err)
+	}
+	if *checkAll {
+		enableAllChecks(opts)
+		*checkGitleaks = true
+	}
+	if *gitleaksConfig != "" && !*checkGitleaks {
+		fatal("--gitleaks-config requires --check-gitleaks")
+	}
+	if *checkGitleaks {
+		if opts.gitleaks, err = newGitleaksDetector(*gitleaksConfig); err != nil {
+			fatal("Error loading gitleaks rules", "err", err)
+		}
+	}
+	if *checkLabelColor != (*labelPalette != "") {
+		fatal("--check-label-color and --label-palette must be given together")
+	}
+	if *checkLabelColor {
+		if opts.labelPalette, err = loadLabelPalette(*labelPalette); err != nil {
+			fatal("Error loading --label-palette", "err", err)
+		}
+	}
+	if opts.summaryMaxRecent < -1 {
+		fatal("--summary-max-recent must be -1 (all) or a non-negative number")
+	}
+	if opts.recipientCap < 0 {
+		fatal("--recipient-cap must not be negative")
+	}
+	switch opts.capPeriod {
+	case capPeriodRun, capPeriodMonth, capPeriodYear:
+	default:
+		fatal("Unknown --cap-period (available: run, month, year)", "cap_period", opts.capPeriod)
+	}
+	if _, err := parseLocale(opts.locale); err != nil {
+		fatal("Invalid --locale", "locale", opts.locale, "err", err)
+	}
+	if opts.csvMode != csvOverwrite && opts.csvMode != csvAppend {
+		fatal("Unknown --csv-mode (available: overwrite, append)", "csv_mode", opts.csvMode)
+	}
+	if opts.sqliteMode != sqliteOverwrite && opts.sqliteMode != sqliteAppend {
+		fatal("Unknown --sqlite-mode (available: overwrite, append)", "sqlite_mode", opts.sqliteMode)
+	}
+	if opts.assigneesFormat != "join" && opts.assigneesFormat != "expand" {
+		fatal("Unknown --assignees-format (available: join, expand)", "assignees_format", opts.assigneesFormat)
+	}
+	switch opts.summaryTableFormat {
+	case summaryTablePlain, summaryTableMarkdown, summaryTableASCII:
+	default:
+		fatal("Unknown --summary-table-format (available: plain, markdown-table, ascii-table)", "summary_table_format", opts.summaryTableFormat)
+	}
+	if *titleTemplate != "" {
+		tmpl, err := template.New("item-title-template").Parse(*titleTemplate)
+		if err != nil {
+			fatal("Invalid --item-title-template", "err", err)
+		}
+		opts.titleTemplate = tmpl
+	}
+	if opts.watchOutputDir != "" && opts.watch <= 0 {
+		fatal("--watch-output-dir requires --watch")
+	}
+	if opts.watchAlertThreshold != 0 && opts.watch <= 0 {
+		fatal("--watch-alert-threshold requires --watch")
+	}
+	if opts.watchAlertThreshold < 0 {
+		fatal("--watch-alert-threshold must not be negative")
+	}
+	opts.formats = splitList(*format)
+	if *outputFormat != "" {
+		if isFlagSet("format") {
+			fatal("--output-format and --format cannot be combined")
+		}
+		switch *outputFormat {
+		case "csv", "json", "markdown":
+			opts.formats = []string{*outputFormat}
+		case "all":
+			opts.formats = []string{"csv", "json", "markdown"}
+		default:
+			fatal("Unknown --output-format (available: csv, json, markdown, all)", "output_format", *outputFormat)
+		}
+	}
+	if slices.Contains(opts.formats, streamFormat) {
+		opts.streamItems = true
+		statusOut = os.Stderr
+	}
+	if opts.fromCache && opts.streamItems {
+		fatal("--from-cache cannot be combined with --format " + streamFormat)
+	}
+	if opts.fromCache && opts.checkLinkedPR && !*checkAll {
+		fatal("--check-linked-pr cannot be combined with --from-cache, which does not store linked pull requests")
+	}
+	if opts.fromCache && *checkLabelColor {
+		fatal("--check-label-color cannot be combined with --from-cache, which does not store label colors")
+	}
+	if opts.fromCache && slices.Contains(opts.formats, deltaFormat) {
+		fatal("--format " + deltaFormat + " cannot be combined with --from-cache, which reads the export it would compare with")
+	}
+	if opts.fromCache && opts.retryOnEmpty {
+		fatal("--retry-on-empty cannot be combined with --from-cache")
+	}
+	// Inside GitHub Actions, also publish the job summary unless asked already.
+	if os.Getenv("GITHUB_STEP_SUMMARY") != "" && !slices.Contains(opts.formats, "github-actions-summary") {
+		opts.formats = append(opts.formats, "github-actions-summary")
+	}
+	if slices.Contains(opts.formats, "sepa-xml") {
+		if opts.sepaDebtorIBAN == "" || opts.sepaDebtorName == "" {
+			fatal("--format sepa-xml requires --sepa-debtor-iban and --sepa-debtor-name")
+		}
+		if !validIBAN(normalizeIBAN(opts.sepaDebtorIBAN)) {
+			fatal("--sepa-debtor-iban is not a valid IBAN", "sepa_debtor_iban", opts.sepaDebtorIBAN)
+		}
+	}
+	if slices.Contains(opts.formats, "ldif") && strings.TrimSpace(opts.ldifBaseDN) == "" {
+		fatal("--format ldif requires a non-empty --ldif-base-dn")
+	}
+	if _, err := parseTokenDecimals(opts.normalizeDecimals); err != nil {
+		fatal("Invalid --normalize-decimals", "err", err)
+	}
+	if slices.Contains(opts.formats, "csv-normalized") && len(opts.normalizeDecimals) == 0 {
+		fatal("--format csv-normalized requires --normalize-decimals")
+	}
+	formats := outputFormats(opts)
+	for _, name := range opts.formats {
+		if _, ok := formats[name]; !ok {
+			fatal("Unknown --format", "format", name, "available", strings.Join(formatNames(), ", "))
+		}
+	}
+	if opts.fromCache && slices.Contains(opts.formats, "sqlite") && samePath(outputPath(formats["sqlite"].filename, opts, time.Now()), opts.cacheDB) {
+		// Re-exporting the cache into itself would stamp stale items with a
+		// fresh exported_at and defeat --cache-max-age.
+		fatal("--format sqlite would overwrite the --cache-db that --from-cache reads; choose a different --cache-db or --output-dir")
+	}
+	return opts
 }
 
 func main() {
-	// Get GitHub token from environment variable
+	opts := parseFlags()
+
+	if opts.verifyCSVHash != "" {
+		rows, tampered, err := verifyCSVHash(opts.verifyCSVHash)
+		if err != nil {
+			fatal("Error verifying CSV hashes", "file", opts.verifyCSVHash, "err", err)
+		}
+		for _, t := range tampered {
+			slog.Error("Tampered row", "row", t)
+		}
+		if len(tampered) > 0 {
+			fatal("Rows failed hash verification", "file", opts.verifyCSVHash, "tampered", len(tampered), "rows", rows)
+		}
+		fmt.Printf("All %d rows in %s match their row_hash\n", rows, opts.verifyCSVHash)
+		return
+	}
+	if opts.decodeCSVBase64 != "" {
+		if err := decodeCSVBase64(opts.decodeCSVBase64, os.Stdout); err != nil {
+			fatal("Error decoding csv-base64 file", "file", opts.decodeCSVBase64, "err", err)
+		}
+		return
+	}
+
+	// Get GitHub token from environment variable, falling back to GitHub App
+	// authentication and then to the config file
 	token := os.Getenv("GITHUB_TOKEN")
-	if token == "" {
-		log.Fatal("GitHub token not found. Set the GITHUB_TOKEN environment variable.")
+	useApp := token == "" && opts.appID != ""
+	if token == "" && !useApp {
+		token = opts.githubToken
+	}
+	if token == "" && !useApp && !opts.fromCache {
+		fatal("GitHub token not found. Set the GITHUB_TOKEN environment variable or github_token in the config file, or authenticate as a GitHub App with --app-id, --installation-id and --private-key-file.")
 	}
 
 	// Create GitHub client
-	ctx := context.Background()
+	// Cancel in-flight requests and stop between pages or files on Ctrl-C.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if opts.noTLSVerify {
+		slog.Warn("--no-tls-verify is set. TLS certificates of GitHub requests are NOT verified; never use this against a production instance.")
+		ctx = withInsecureTLS(ctx)
+	}
 	src := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	)
+	if useApp {
+		app, err := newAppTokenSource(ctx, restURLFor(opts.graphqlEndpoint), opts.appID, opts.installationID, opts.privateKeyFile)
+		if err != nil {
+			fatal("Error reading --private-key-file", "file", opts.privateKeyFile, "err", err)
+		}
+		src = oauth2.ReuseTokenSource(nil, app)
+	}
 	httpClient := oauth2.NewClient(ctx, src)
-	client := githubv4.NewClient(httpClient)
-
-	// Project details
-	org := "NautilusOSS"
-	projectNumber := 2
-
-	// Get project ID
-	projectID, err := getProjectID(ctx, client, org, projectNumber)
-	if err != nil {
-		log.Fatalf("Error getting project ID: %v", err)
+	if opts.githubDebug {
+		httpClient.Transport = &tracingTransport{next: httpClient.Transport}
+	}
+	httpClient.Transport = &rateLimitTransport{next: httpClient.Transport, threshold: opts.rateLimitThreshold}
+	var client GraphQLClient = githubv4.NewClient(httpClient)
+	if opts.graphqlEndpoint != "" {
+		client = githubv4.NewEnterpriseClient(opts.graphqlEndpoint, httpClient)
+	}
+	if opts.cacheDir != "" && !opts.noCache {
+		client = newCachingClient(client, opts.cacheDir, opts.cacheTTL)
 	}
-	fmt.Printf("Project ID: %s\n", projectID)
 
-	// Get project items
-	items, err := getProjectItems(ctx, client, projectID)
-	if err != nil {
-		log.Fatalf("Error getting project items: %v", err)
+	if opts.checkTokenConfig {
+		if err := checkPaymentTokenConfig(ctx, opts.ethereumRPC, opts.tokenAddress, opts.chainID); err != nil {
+			fatal("Payment token config check failed", "err", err)
+		}
 	}
-	fmt.Printf("Found %d 'Pending Payment' items in the project\n", len(items))
 
-	// Generate CSV file
-	if err := generateCSV(items, "pending_payment_tasks.csv"); err != nil {
-		log.Fatalf("Error generating CSV: %v", err)
+	if opts.outputDir != "" && !dryRun {
+		if err := os.MkdirAll(opts.outputDir, 0o755); err != nil {
+			fatal("Error creating --output-dir", "dir", opts.outputDir, "err", err)
+		}
 	}
-	fmt.Println("CSV file generated: pending_payment_tasks.csv")
 
-	// Generate summary report
-	if err := generateSummaryReport(items, "pending_payment_summary.txt"); err != nil {
-		log.Fatalf("Error generating summary report: %v", err)
+	if opts.watch > 0 {
+		watch(ctx, client, httpClient, opts)
+		return
+	}
+	if _, err := run(ctx, client, httpClient, opts); err != nil {
+		if errors.Is(err, errMissingRecipients) {
+			slog.Error("Export finished with missing recipients", "err", err)
+			os.Exit(2)
+		}
+		fatal("Export failed", "err", err)
 	}
-	fmt.Println("Summary report generated: pending_payment_summary.txt")
 }
 
-func getProjectID(ctx context.Context, client *githubv4.Client, org string, projectNumber int) (string, error) {
-	var query struct {
-		Organization struct {
-			ProjectV2 struct {
-				ID string
-			} `graphql:"projectV2(number: $number)"`
-		} `graphql:"organization(login: $login)"`
+// fetchAndFilter fetches the items, or reads them from --cache-db, and
+// narrows them down by --since-last-run, --updated-after/--updated-before
+// and --recent.
+func fetchAndFilter(ctx context.Context, client GraphQLClient, opts *options, state runState) ([]ProjectItem, error) {
+	var items []ProjectItem
+	var err error
+	if opts.fromCache {
+		var exportedAt time.Time
+		if items, exportedAt, err = loadSQLiteItems(opts.cacheDB, opts.cacheMaxAge); err != nil {
+			return nil, fmt.Errorf("error reading --cache-db: %w", err)
+		}
+		fmt.Fprintf(statusOut, "Found %d items in %s, exported at %s\n", len(items), opts.cacheDB, exportedAt.Format(time.RFC3339))
+	} else {
+		if items, err = fetchItems(ctx, client, opts); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(statusOut, "Found %d %s items in the project\n", len(items), describeStatuses(opts.statuses))
+	}
+	if items, err = validateFieldValues(items, opts.ignoreParseErrors); err != nil {
+		return nil, err
 	}
 
-	variables := map[string]interface{}{
-		"login":  githubv4.String(org),
-		"number": githubv4.Int(projectNumber),
+	if opts.sinceLastRun && !state.LastRunAt.IsZero() {
+		items = updatedAfter(items, state.LastRunAt)
+		fmt.Fprintf(statusOut, "%d items updated since the last run at %s\n", len(items), state.LastRunAt.Format(time.RFC3339))
 	}
 
-	err := client.Query(ctx, &query, variables)
-	if err != nil {
-		return "", err
+	// The ProjectV2 items connection has no updatedAt filter argument, so the
+	// window is applied after fetching.
+	if !opts.updatedAfter.IsZero() || !opts.updatedBefore.IsZero() {
+		items = updatedWithin(items, opts.updatedAfter, opts.updatedBefore)
+		fmt.Fprintf(statusOut, "%d items updated within the requested window\n", len(items))
 	}
 
-	return query.Organization.ProjectV2.ID, nil
+	if opts.recent > 0 {
+		items = mostRecent(items, opts.recent)
+	}
+	return items, nil
 }
 
-func getProjectItems(ctx context.Context, client *githubv4.Client, projectID string) ([]ProjectItem, error) {
-	var query struct {
-		Node struct {
-			ProjectV2 struct {
-				Items struct {
-					Nodes []struct {
-						ID          string
-						FieldValues struct {
-							Nodes []struct {
-								// We need to use fragments for union types
-								Status struct {
-									Name string
-								} `graphql:"... on ProjectV2ItemFieldSingleSelectValue"`
-								Text struct {
-									Text string
-								} `graphql:"... on ProjectV2ItemFieldTextValue"`
-								Number struct {
-									Number float64
-								} `graphql:"... on ProjectV2ItemFieldNumberValue"`
-							}
-						} `graphql:"fieldValues(first: 100)"`
-						Content struct {
-							Issue struct {
-								Title     string
-								URL       string
-								CreatedAt time.Time
-								UpdatedAt time.Time
-								Body      string
-								Assignees struct {
-									Nodes []struct {
-										Login string
-									}
-								} `graphql:"assignees(first: 100)"`
-								Labels struct {
-									Nodes []struct {
-										Name string
-									}
-								} `graphql:"labels(first: 100)"`
-							} `graphql:"... on Issue"`
-						}
-					}
-				} `graphql:"items(first: 100)"`
-			} `graphql:"... on ProjectV2"`
-		} `graphql:"node(id: $id)"`
-	}
-
-	variables := map[string]interface{}{
-		"id": githubv4.ID(projectID),
-	}
-
-	err := client.Query(ctx, &query, variables)
-	if err != nil {
-		return nil, err
+// run performs a single export: it fetches the matching project items,
+// checks them and writes every requested output file. It returns the number
+// of items selected for export.
+func run(ctx context.Context, client GraphQLClient, httpClient *http.Client, opts *options) (int, error) {
+	// Record the start time so items updated while this run is in flight are
+	// picked up again by the next --since-last-run.
+	startedAt := time.Now()
+	var state runState
+	if opts.sinceLastRun {
+		var err error
+		if state, err = loadState(); err != nil {
+			return 0, fmt.Errorf("error reading run state: %w", err)
+		}
 	}
 
-	var items []ProjectItem
-	for _, node := range query.Node.ProjectV2.Items.Nodes {
-		issue := node.Content.Issue
-		// Check if the item is in "Pending Payment" status
-		isPendingPayment := false
-		var recipient string
-		var bountyAmount string
-		var bountySymbol string
-
-		for _, fieldValue := range node.FieldValues.Nodes {
-			if fieldValue.Status.Name == "Pending Payment" {
-				isPendingPayment = true
-			}
-			// Check for recipient field (text field)
-			if fieldValue.Text.Text != "" {
-				// Check if this text field contains a bounty value
-				if strings.HasSuffix(strings.TrimSpace(fieldValue.Text.Text), "BUIDL") {
-					parts := strings.Fields(fieldValue.Text.Text)
-					if len(parts) == 2 {
-						bountyAmount = parts[0]
-						bountySymbol = parts[1]
-					}
-				} else if !strings.Contains(fieldValue.Text.Text, "BUIDL") {
-					// Only set as recipient if it's not a bounty value
-					recipient = fieldValue.Text.Text
-				}
-			}
-			// Keep the number field check as a fallback
-			if fieldValue.Number.Number > 0 {
-				bountyAmount = fmt.Sprintf("%.0f", fieldValue.Number.Number)
-				bountySymbol = "BUIDL"
-			}
+	items, err := fetchAndFilter(ctx, client, opts, state)
+	if err != nil {
+		return 0, err
+	}
+	if opts.retryOnEmpty && len(items) == 0 {
+		// A cached response would only repeat the empty result.
+		if c, ok := client.(*cachingClient); ok {
+			client = c.next
 		}
-
-		if isPendingPayment {
-			assignees := make([]string, len(issue.Assignees.Nodes))
-			for i, a := range issue.Assignees.Nodes {
-				assignees[i] = a.Login
+		for attempt := 1; len(items) == 0 && attempt <= opts.retryCount; attempt++ {
+			slog.Info("No items matched; retrying", "delay", opts.retryDelay, "attempt", attempt, "retry_count", opts.retryCount)
+			select {
+			case <-time.After(opts.retryDelay):
+			case <-ctx.Done():
+				return 0, ctx.Err()
 			}
-			labels := make([]string, len(issue.Labels.Nodes))
-			for i, l := range issue.Labels.Nodes {
-				labels[i] = l.Name
+			if items, err = fetchAndFilter(ctx, client, opts, state); err != nil {
+				return 0, err
 			}
-
-			items = append(items, ProjectItem{
-				ID:           node.ID,
-				Title:        issue.Title,
-				URL:          issue.URL,
-				CreatedAt:    issue.CreatedAt,
-				UpdatedAt:    issue.UpdatedAt,
-				AssignedTo:   assignees,
-				Labels:       labels,
-				Description:  issue.Body,
-				Recipient:    recipient,
-				BountyAmount: bountyAmount,
-				BountySymbol: bountySymbol,
-			})
 		}
 	}
 
-	return items, nil
-}
+	if opts.verifySignatures {
+		verifySignatures(ctx, httpClient, restURLFor(opts.graphqlEndpoint), items)
+	}
 
-func generateCSV(items []ProjectItem, filename string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
+	if err := reportFindings(runChecks(ctx, client, httpClient, items, opts), opts.strict); err != nil {
+		return 0, err
+	}
+	// Checks report request failures as findings; don't export if those
+	// failures were caused by cancellation.
+	if err := ctx.Err(); err != nil {
+		return 0, err
 	}
-	defer file.Close()
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+	if opts.titleTemplate != nil {
+		if err := applyTitleTemplate(items, opts.titleTemplate); err != nil {
+			return 0, err
+		}
+	}
 
-	// Write header
-	header := []string{"ID", "Title", "URL", "Created At", "Updated At", "Due Date", "Description", "Recipient", "Bounty Amount", "Bounty Symbol"}
-	if err := writer.Write(header); err != nil {
-		return err
+	if opts.preview && !confirmExport(items) {
+		fmt.Fprintln(statusOut, "Export cancelled")
+		return len(items), nil
 	}
 
-	// Write data
-	for _, item := range items {
-		row := []string{
-			item.ID,
-			item.Title,
-			item.URL,
-			item.CreatedAt.Format(time.RFC3339),
-			item.UpdatedAt.Format(time.RFC3339),
-			item.DueDate,
-			item.Description,
-			item.Recipient,
-			item.BountyAmount,
-			item.BountySymbol,
+	// Generate the requested output files, followed by the summary report
+	now := time.Now()
+	formats := outputFormats(opts)
+	var jobs []exportJob
+	for _, name := range opts.formats {
+		format := formats[name]
+		if format.generate == nil {
+			continue
+		}
+		jobs = append(jobs, exportJob{name: name, filename: outputPath(format.filename, opts, now), generate: format.generate})
+	}
+	if slices.Contains(opts.formats, deltaFormat) {
+		previous, err := loadPreviousExport(opts.cacheDB)
+		if err != nil {
+			return 0, fmt.Errorf("error reading --cache-db for %s: %w", deltaFormat, err)
 		}
-		if err := writer.Write(row); err != nil {
-			return err
+		// The next run compares with this export, so it has to end up in
+		// --cache-db. A sqlite job writing that file records it already.
+		baseline := opts.cacheDB
+		if slices.Contains(opts.formats, "sqlite") && samePath(outputPath(formats["sqlite"].filename, opts, now), opts.cacheDB) {
+			baseline = ""
+		}
+		jobs = append(jobs, exportJob{
+			name:     deltaFormat,
+			filename: outputPath(formats[deltaFormat].filename, opts, now),
+			generate: func(items []ProjectItem, filename string) error {
+				return generateCSVDelta(items, previous, filename, baseline, opts.sqliteMode)
+			},
+		})
+	}
+	summaryOpts := summaryOptions{
+		tableFormat:  opts.summaryTableFormat,
+		maxRecent:    opts.summaryMaxRecent,
+		recipientCap: opts.recipientCap,
+		capPeriod:    opts.capPeriod,
+		symbol:       opts.bountySymbol,
+	}
+	jobs = append(jobs, exportJob{
+		name:     "summary",
+		filename: outputPath("pending_payment_summary.txt", opts, now),
+		generate: func(items []ProjectItem, filename string) error {
+			return generateSummaryReport(items, filename, summaryOpts)
+		},
+	})
+
+	if opts.concurrentExports {
+		err = exportConcurrently(ctx, jobs, items)
+	} else {
+		for _, job := range jobs {
+			if err = job.run(ctx, items); err != nil {
+				break
+			}
 		}
 	}
-
-	return nil
-}
-
-func generateSummaryReport(items []ProjectItem, filename string) error {
-	file, err := os.Create(filename)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	defer file.Close()
 
-	totalBounty := 0.0
-	for _, item := range items {
-		if item.BountyAmount != "" {
-			bountyValue := 0.0
-			fmt.Sscanf(item.BountyAmount, "%f", &bountyValue)
-			totalBounty += bountyValue
+	if opts.sinceLastRun && !dryRun {
+		if err := saveState(runState{LastRunAt: startedAt}); err != nil {
+			return 0, fmt.Errorf("error saving run state: %w", err)
 		}
 	}
+	if missing := missingRecipients(items); len(missing) > 0 {
+		for _, item := range missing {
+			slog.Warn("Item has no recipient", "title", item.Title, "url", item.URL)
+		}
+		return len(items), fmt.Errorf("%d item(s): %w", len(missing), errMissingRecipients)
+	}
+	return len(items), nil
+}
 
-	// Write summary
-	fmt.Fprintf(file, "# Project Summary Report\n")
-	fmt.Fprintf(file, "Generated on: %s\n\n", time.Now().Format(time.RFC1123))
+// stringList is a flag.Value that collects repeated and comma-separated
+// occurrences of a flag.
+type stringList []string
 
-	fmt.Fprintf(file, "## Overview\n")
-	fmt.Fprintf(file, "Total Items: %d\n", len(items))
-	fmt.Fprintf(file, "Total Bounty Value: %.0f BUIDL\n\n", totalBounty)
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
 
-	fmt.Fprintf(file, "## Items by Recipient\n")
-	recipientMap := make(map[string]float64)
-	for _, item := range items {
-		if item.Recipient != "" {
-			bountyValue := 0.0
-			fmt.Sscanf(item.BountyAmount, "%f", &bountyValue)
-			recipientMap[item.Recipient] += bountyValue
-		}
-	}
-	for recipient, amount := range recipientMap {
-		fmt.Fprintf(file, "- %s: %.0f BUIDL\n", recipient, amount)
-	}
-	fmt.Fprintf(file, "\n")
+func (l *stringList) Set(value string) error {
+	*l = append(*l, splitList(value)...)
+	return nil
+}
 
-	fmt.Fprintf(file, "## Recent Activity\n")
-	count := 0
-	for _, item := range items {
-		if count >= 5 {
-			break
+// isFlagSet reports whether the named flag was given on the command line.
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
 		}
-		fmt.Fprintf(file, "- %s (Updated: %s) - Recipient: %s, Bounty: %s %s\n",
-			item.Title,
-			item.UpdatedAt.Format("2006-01-02"),
-			item.Recipient,
-			item.BountyAmount,
-			item.BountySymbol,
-		)
-		count++
+	})
+	return set
+}
+
+// describeStatuses formats a --status filter for progress messages, e.g.
+// 'Pending Payment' or 'Approved'/'Ready for Payout'.
+func describeStatuses(statuses []string) string {
+	if len(statuses) == 0 {
+		return "matching"
+	}
+	quoted := make([]string, len(statuses))
+	for i, s := range statuses {
+		quoted[i] = "'" + s + "'"
 	}
+	return strings.Join(quoted, "/")
+}
 
-	return nil
+// samePath reports whether a and b name the same file.
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
 }
 
-func truncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
 	}
-	return s[:maxLen] + "..."
+	return values
 }
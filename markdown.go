@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// generateActionsSummary appends the payment summary as Markdown tables to
+// filename, normally the file named by $GITHUB_STEP_SUMMARY, so it shows up
+// on the GitHub Actions job page. Actions expects steps to append to this
+// file rather than replace it. Totals are in symbol, the --bounty-symbol.
+func generateActionsSummary(items []ProjectItem, filename, symbol string) error {
+	if filename == "" {
+		return fmt.Errorf("GITHUB_STEP_SUMMARY is not set")
+	}
+	file, err := appendOutput(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Abort()
+
+	fmt.Fprintf(file, "## Pending Payments\n\n")
+	fmt.Fprintf(file, "**%d** items, **%.0f %s** in total.\n\n", len(items), totalBounty(items), symbol)
+
+	fmt.Fprintf(file, "### Items by Recipient\n\n")
+	fmt.Fprintf(file, "| Recipient | Total |\n|---|---:|\n")
+	recipients := bountyByRecipient(items)
+	for _, recipient := range sortedKeys(recipients) {
+		fmt.Fprintf(file, "| %s | %.0f %s |\n", escapeMarkdownCell(recipient), recipients[recipient], symbol)
+	}
+
+	fmt.Fprintf(file, "\n### Items\n\n")
+	fmt.Fprintf(file, "| Title | Recipient | Bounty | Updated |\n|---|---|---:|---|\n")
+	for _, item := range items {
+		fmt.Fprintf(file, "| [%s](%s) | %s | %s %s | %s |\n",
+			escapeMarkdownCell(item.Title),
+			item.URL,
+			escapeMarkdownCell(item.Recipient),
+			item.BountyAmount,
+			item.BountySymbol,
+			item.UpdatedAt.Format("2006-01-02"),
+		)
+	}
+	fmt.Fprintf(file, "\n")
+
+	return file.Close()
+}
+
+// escapeMarkdownCell makes s safe to place in a GFM table cell.
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r\n", " ")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// generateMarkdownTable writes a bare GFM table of the items, with no
+// heading or prose around it, for pasting into a PR description or Notion
+// page. columns selects and orders the table's columns.
+func generateMarkdownTable(items []ProjectItem, filename string, columns []csvColumn) error {
+	file, err := createOutput(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Abort()
+
+	header := make([]string, len(columns))
+	align := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = escapeMarkdownCell(col.Name)
+		align[i] = "---"
+		if col.Type == "number" {
+			align[i] = "---:"
+		}
+	}
+	fmt.Fprintf(file, "| %s |\n|%s|\n", strings.Join(header, " | "), strings.Join(align, "|"))
+	for _, item := range items {
+		row := make([]string, len(columns))
+		for i, col := range columns {
+			row[i] = escapeMarkdownCell(col.Value(item))
+		}
+		fmt.Fprintf(file, "| %s |\n", strings.Join(row, " | "))
+	}
+	return file.Close()
+}
+
+// generateMarkdownReport writes the summary report as GitHub-Flavored
+// Markdown for a release description or issue comment: totals per recipient
+// in a table, then every item in a collapsible <details> section so long
+// reports stay readable. Totals are in symbol, the --bounty-symbol.
+func generateMarkdownReport(items []ProjectItem, filename, symbol string) error {
+	file, err := createOutput(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Abort()
+
+	fmt.Fprintf(file, "# Project Summary Report\n\n")
+	fmt.Fprintf(file, "_Generated on %s_\n\n", time.Now().Format(time.RFC1123))
+	fmt.Fprintf(file, "**%d** items, **%.0f %s** in total.\n\n", len(items), totalBounty(items), symbol)
+
+	fmt.Fprintf(file, "## Items by Recipient\n\n")
+	fmt.Fprintf(file, "| Recipient | Total |\n|---|---:|\n")
+	recipients := bountyByRecipient(items)
+	for _, recipient := range sortedKeys(recipients) {
+		fmt.Fprintf(file, "| %s | %.0f %s |\n", escapeMarkdownCell(recipient), recipients[recipient], symbol)
+	}
+
+	fmt.Fprintf(file, "\n<details>\n<summary>All %d items</summary>\n\n", len(items))
+	for _, item := range items {
+		fmt.Fprintf(file, "- [%s](%s): %s %s to %s\n",
+			escapeMarkdownCell(item.Title),
+			item.URL,
+			item.BountyAmount,
+			item.BountySymbol,
+			escapeMarkdownCell(item.Recipient),
+		)
+	}
+	fmt.Fprintf(file, "\n</details>\n")
+
+	return file.Close()
+}
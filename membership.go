@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// checkOrgMembership reports assignees who are not members of the
+// organization their item was exported from. Membership is resolved with
+// user(login:) { organization(login:) }, which is only visible for public
+// memberships or when the token can read the organization's members.
+func checkOrgMembership(ctx context.Context, client GraphQLClient, items []ProjectItem) []finding {
+	type membership struct{ org, login string }
+	seen := make(map[membership]bool)
+	var pairs []membership
+	for _, item := range items {
+		for _, login := range item.AssignedTo {
+			m := membership{item.Org, login}
+			if !seen[m] {
+				seen[m] = true
+				pairs = append(pairs, m)
+			}
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].org != pairs[j].org {
+			return pairs[i].org < pairs[j].org
+		}
+		return pairs[i].login < pairs[j].login
+	})
+
+	var findings []finding
+	for _, m := range pairs {
+		if ctx.Err() != nil {
+			break
+		}
+		isMember, err := isOrgMember(ctx, client, m.org, m.login)
+		switch {
+		case err != nil:
+			findings = append(findings, finding{check: "org-membership", message: fmt.Sprintf("could not check %s in %s: %v", m.login, m.org, err)})
+		case !isMember:
+			findings = append(findings, finding{check: "org-membership", message: fmt.Sprintf("assignee %s is not a member of %s", m.login, m.org)})
+		}
+	}
+	return findings
+}
+
+func isOrgMember(ctx context.Context, client GraphQLClient, org, login string) (bool, error) {
+	var query struct {
+		User struct {
+			Organization *struct {
+				Login string
+			} `graphql:"organization(login: $org)"`
+		} `graphql:"user(login: $login)"`
+	}
+
+	variables := map[string]interface{}{
+		"login": githubv4.String(login),
+		"org":   githubv4.String(org),
+	}
+
+	if err := queryWithRetry(ctx, client, &query, variables, maxQueryRetries); err != nil {
+		return false, err
+	}
+	return query.User.Organization != nil, nil
+}
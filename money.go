@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// parseLocale parses a --locale value such as de_DE or en-US.
+func parseLocale(locale string) (language.Tag, error) {
+	return language.Parse(strings.ReplaceAll(locale, "_", "-"))
+}
+
+// formatMoney formats a bounty amount for people to read, with the thousand
+// separators and decimal mark of the printer's locale followed by the token
+// symbol: 1500.5 BUIDL becomes "1,500.5 BUIDL" in en_US and "1.500,5 BUIDL"
+// in de_DE. Amounts such as 1,500 or 1.5k are first read with
+// canonicalBountyAmount. The fractional digits are kept exactly as given
+// rather than going through a float. Amounts that are not decimal numbers
+// are returned unchanged, apart from the symbol.
+func formatMoney(p *message.Printer, amount, symbol string) string {
+	if amount == "" {
+		return ""
+	}
+	formatted := amount
+	if canonical, err := canonicalBountyAmount(amount); err == nil {
+		amount = canonical
+	}
+	digits, negative := strings.CutPrefix(amount, "-")
+	whole, fraction, hasFraction := strings.Cut(digits, ".")
+	if n, err := strconv.ParseUint(whole, 10, 64); err == nil && isDigits(fraction) && (!hasFraction || fraction != "") {
+		formatted = p.Sprint(number.Decimal(n))
+		if hasFraction {
+			formatted += decimalMark(p) + fraction
+		}
+		if negative {
+			formatted = "-" + formatted
+		}
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %s", formatted, symbol))
+}
+
+// decimalMark returns the decimal separator of the printer's locale.
+func decimalMark(p *message.Printer) string {
+	half := p.Sprint(number.Decimal(0.5))
+	return strings.TrimSuffix(strings.TrimPrefix(half, "0"), "5")
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
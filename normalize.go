@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// parseTokenDecimals parses a --normalize-decimals value such as
+// BUIDL:18,USDC:6 into a map from token symbol to decimals.
+func parseTokenDecimals(entries []string) (map[string]int, error) {
+	decimals := make(map[string]int, len(entries))
+	for _, entry := range entries {
+		symbol, d, ok := strings.Cut(entry, ":")
+		n, err := strconv.Atoi(d)
+		if !ok || symbol == "" || err != nil || n < 0 {
+			return nil, fmt.Errorf("%q is not SYMBOL:DECIMALS", entry)
+		}
+		decimals[symbol] = n
+	}
+	return decimals, nil
+}
+
+// normalizeAmount converts a decimal token amount into the integer amount of
+// the contract's smallest unit, e.g. 1.5 USDC with 6 decimals is 1500000.
+// Amounts are first read with canonicalBountyAmount, so 1,500 and 1.5k are
+// accepted. The conversion is exact; amounts with more fractional digits
+// than the token has decimals are rejected rather than rounded.
+func normalizeAmount(amount, symbol string, decimals map[string]int) (string, error) {
+	d, ok := decimals[symbol]
+	if !ok {
+		return "", fmt.Errorf("no decimals configured for token %q", symbol)
+	}
+	canonical, err := canonicalBountyAmount(amount)
+	if err != nil {
+		return "", err
+	}
+	r, ok := new(big.Rat).SetString(canonical)
+	if !ok {
+		return "", fmt.Errorf("bounty amount %q is not a number", amount)
+	}
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d)), nil)))
+	if !r.IsInt() {
+		return "", fmt.Errorf("bounty amount %s %s has more than %d decimals", amount, symbol, d)
+	}
+	return r.Num().String(), nil
+}
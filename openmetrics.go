@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// generateOpenMetrics writes gauges describing the pending payments in the
+// OpenMetrics text format, for scraping with a textfile collector or
+// Prometheus federation. Every metric carries # TYPE and # HELP metadata,
+// plus # UNIT where it has one, and the exposition ends with # EOF.
+func generateOpenMetrics(items []ProjectItem, filename string) error {
+	file, err := createOutput(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Abort()
+
+	type bountyKey struct{ org, symbol string }
+	itemsByOrg := make(map[string]int)
+	bounties := make(map[bountyKey]float64)
+	var lastUpdated int64
+	for _, item := range items {
+		itemsByOrg[item.Org]++
+		if item.BountyAmount != "" {
+			bountyValue, _ := parseBountyAmount(item.BountyAmount)
+			bounties[bountyKey{item.Org, item.BountySymbol}] += bountyValue
+		}
+		if t := item.UpdatedAt.Unix(); t > lastUpdated {
+			lastUpdated = t
+		}
+	}
+
+	w := bufio.NewWriter(file)
+	fmt.Fprintln(w, "# TYPE buidl_pending_payment_items gauge")
+	fmt.Fprintln(w, "# HELP buidl_pending_payment_items Number of project items awaiting payment.")
+	orgs := make([]string, 0, len(itemsByOrg))
+	for org := range itemsByOrg {
+		orgs = append(orgs, org)
+	}
+	sort.Strings(orgs)
+	for _, org := range orgs {
+		fmt.Fprintf(w, "buidl_pending_payment_items{org=\"%s\"} %d\n", escapeLabelValue(org), itemsByOrg[org])
+	}
+
+	fmt.Fprintln(w, "# TYPE buidl_pending_payment_bounty gauge")
+	fmt.Fprintln(w, "# HELP buidl_pending_payment_bounty Summed bounty awaiting payment, in units of the token named by the symbol label.")
+	keys := make([]bountyKey, 0, len(bounties))
+	for key := range bounties {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].org != keys[j].org {
+			return keys[i].org < keys[j].org
+		}
+		return keys[i].symbol < keys[j].symbol
+	})
+	for _, key := range keys {
+		fmt.Fprintf(w, "buidl_pending_payment_bounty{org=\"%s\",symbol=\"%s\"} %g\n",
+			escapeLabelValue(key.org), escapeLabelValue(key.symbol), bounties[key])
+	}
+
+	if len(items) > 0 {
+		fmt.Fprintln(w, "# TYPE buidl_pending_payment_last_updated_seconds gauge")
+		fmt.Fprintln(w, "# UNIT buidl_pending_payment_last_updated_seconds seconds")
+		fmt.Fprintln(w, "# HELP buidl_pending_payment_last_updated_seconds Unix time of the most recent item update.")
+		fmt.Fprintf(w, "buidl_pending_payment_last_updated_seconds %d\n", lastUpdated)
+	}
+	fmt.Fprintln(w, "# EOF")
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// escapeLabelValue escapes backslashes, double quotes and newlines as the
+// OpenMetrics text format requires inside label values.
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
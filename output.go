@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+
+	"golang.org/x/text/message"
+)
+
+// streamFormat writes items to stdout as NDJSON while they are fetched
+// instead of producing a file; see fetchItems.
+const streamFormat = "ndjson-stream"
+
+// outputFormat describes one file that can be produced with --format. A nil
+// generate marks a format that is handled outside the export jobs.
+type outputFormat struct {
+	filename string
+	generate func(items []ProjectItem, filename string) error
+}
+
+// taggedFormats holds formats registered by files behind build tags, such as
+// pdf, as functions configuring them for opts. They are merged into the
+// result of outputFormats.
+var taggedFormats = map[string]func(opts *options) outputFormat{}
+
+// outputFormats returns the available output formats configured for opts.
+func outputFormats(opts *options) map[string]outputFormat {
+	csvOpts := csvOptions{
+		expandMultiValue: opts.expandMultiValue,
+		sprint:           opts.sprintField != "",
+		signatures:       opts.verifySignatures,
+		expandAssignees:  opts.assigneesFormat == "expand",
+	}
+	plainOpts := csvOpts
+	plainOpts.appendRows = opts.csvMode == csvAppend
+	typedOpts := csvOpts
+	typedOpts.typed = true
+	annotatedOpts := csvOpts
+	annotatedOpts.annotated = true
+	utf16Opts := csvOpts
+	utf16Opts.utf16le = true
+	semicolonOpts := csvOpts
+	semicolonOpts.semicolon = true
+	wideOpts := csvOpts
+	wideOpts.wide = true
+	hashOpts := csvOpts
+	hashOpts.hash = true
+	normalizedOpts := csvOpts
+	normalizedOpts.normalizeDecimals, _ = parseTokenDecimals(opts.normalizeDecimals)
+	base64Opts := csvOpts
+	base64Opts.base64 = true
+	moneyOpts := csvOpts
+	locale, _ := parseLocale(opts.locale)
+	moneyOpts.money = message.NewPrinter(locale)
+	tableColumns, _ := selectColumns(opts.fields)
+	ldifBaseDN := opts.ldifBaseDN
+	symbol := opts.bountySymbol
+	sqliteMode := opts.sqliteMode
+	sepaOpts := sepaOptions{debtorIBAN: opts.sepaDebtorIBAN, debtorName: opts.sepaDebtorName}
+
+	formats := map[string]outputFormat{
+		"csv": {
+			filename: "pending_payment_tasks.csv",
+			generate: func(items []ProjectItem, filename string) error { return generateCSV(items, filename, plainOpts) },
+		},
+		"json":     {filename: "pending_payment_tasks.json", generate: generateJSON},
+		"json-api": {filename: "pending_payment_tasks.jsonapi.json", generate: generateJSONAPI},
+		"csv-typed": {
+			filename: "pending_payment_tasks_typed.csv",
+			generate: func(items []ProjectItem, filename string) error { return generateCSV(items, filename, typedOpts) },
+		},
+		"csv-annotated": {
+			filename: "pending_payment_tasks_annotated.csv",
+			generate: func(items []ProjectItem, filename string) error { return generateCSV(items, filename, annotatedOpts) },
+		},
+		"csv-utf16le": {
+			filename: "pending_payment_tasks_utf16le.csv",
+			generate: func(items []ProjectItem, filename string) error { return generateCSV(items, filename, utf16Opts) },
+		},
+		"csv-wide": {
+			filename: "pending_payment_tasks_wide.csv",
+			generate: func(items []ProjectItem, filename string) error { return generateCSV(items, filename, wideOpts) },
+		},
+		"csv-semicolon": {
+			filename: "pending_payment_tasks_semicolon.csv",
+			generate: func(items []ProjectItem, filename string) error { return generateCSV(items, filename, semicolonOpts) },
+		},
+		"csv-hash": {
+			filename: "pending_payment_tasks_hashed.csv",
+			generate: func(items []ProjectItem, filename string) error { return generateCSV(items, filename, hashOpts) },
+		},
+		"csv-normalized": {
+			filename: "pending_payment_tasks_normalized.csv",
+			generate: func(items []ProjectItem, filename string) error { return generateCSV(items, filename, normalizedOpts) },
+		},
+		"csv-base64": {
+			filename: "pending_payment_tasks_base64.csv",
+			generate: func(items []ProjectItem, filename string) error { return generateCSV(items, filename, base64Opts) },
+		},
+		"csv-money": {
+			filename: "pending_payment_tasks_money.csv",
+			generate: func(items []ProjectItem, filename string) error { return generateCSV(items, filename, moneyOpts) },
+		},
+		"csv-pivot": {
+			filename: "pending_payment_pivot.csv",
+			generate: func(items []ProjectItem, filename string) error {
+				file, err := createOutput(filename)
+				if err != nil {
+					return err
+				}
+				defer file.Abort()
+				if err := generateCSVPivot(items, file); err != nil {
+					return err
+				}
+				return file.Close()
+			},
+		},
+		"markdown": {
+			filename: "pending_payment_report.md",
+			generate: func(items []ProjectItem, filename string) error {
+				return generateMarkdownReport(items, filename, symbol)
+			},
+		},
+		"markdown-table": {
+			filename: "pending_payment_table.md",
+			generate: func(items []ProjectItem, filename string) error {
+				return generateMarkdownTable(items, filename, tableColumns)
+			},
+		},
+		"ical": {filename: "pending_payment_due_dates.ics", generate: generateICal},
+		"github-actions-summary": {
+			filename: os.Getenv("GITHUB_STEP_SUMMARY"),
+			generate: func(items []ProjectItem, filename string) error {
+				return generateActionsSummary(items, filename, symbol)
+			},
+		},
+		streamFormat:    {},
+		deltaFormat:     {filename: "pending_payment_delta.csv"},
+		"recipient-map": {filename: "pending_payment_recipients.json", generate: generateRecipientMap},
+		"sepa-xml": {
+			filename: "pending_payment_transfers.xml",
+			generate: func(items []ProjectItem, filename string) error { return generateSEPAXML(items, filename, sepaOpts) },
+		},
+		"ldif": {
+			filename: "pending_payment_tasks.ldif",
+			generate: func(items []ProjectItem, filename string) error { return generateLDIF(items, filename, ldifBaseDN) },
+		},
+		"sqlite": {
+			filename: "pending_payment_tasks.db",
+			generate: func(items []ProjectItem, filename string) error { return generateSQLite(items, filename, sqliteMode) },
+		},
+		"toml":        {filename: "pending_payment_tasks.toml", generate: generateTOML},
+		"xml":         {filename: "pending_payment_tasks.xml", generate: generateXML},
+		"hcl":         {filename: "pending_payments.tf", generate: generateHCL},
+		"openmetrics": {filename: "pending_payment_metrics.om", generate: generateOpenMetrics},
+	}
+	for name, format := range taggedFormats {
+		formats[name] = format(opts)
+	}
+	return formats
+}
+
+// formatNames returns the registered output format names in sorted order.
+func formatNames() []string {
+	formats := outputFormats(&options{})
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// errSkipOutput is wrapped by generators that have nothing to write, such
+// as ical when no item has a due date. The file is then skipped with a
+// warning instead of failing the run.
+var errSkipOutput = errors.New("skipping output")
+
+// exportJob is one output file to be written by a run.
+type exportJob struct {
+	name     string
+	filename string
+	generate func(items []ProjectItem, filename string) error
+}
+
+func (job exportJob) run(ctx context.Context, items []ProjectItem) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := job.generate(items, job.filename); errors.Is(err, errSkipOutput) {
+		slog.Warn("Output file not written", "format", job.name, "file", job.filename, "reason", err)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("error generating %s output: %w", job.name, err)
+	}
+	if dryRun {
+		fmt.Fprintf(statusOut, "%s file not written (dry run): %s\n", job.name, job.filename)
+		return nil
+	}
+	fmt.Fprintf(statusOut, "%s file generated: %s\n", job.name, job.filename)
+	return nil
+}
+
+// exportConcurrently runs every job in its own goroutine and returns the
+// errors of all failed jobs joined together, so one failing format does not
+// hide problems with the others.
+func exportConcurrently(ctx context.Context, jobs []exportJob, items []ProjectItem) error {
+	errs := make([]error, len(jobs))
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = job.run(ctx, items)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
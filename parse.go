@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// validateFieldValues checks that every item's bounty amount is a number and
+// its due date, if any, is a date. Items that fail are returned as errors,
+// or skipped with a warning when ignore is set.
+func validateFieldValues(items []ProjectItem, ignore bool) ([]ProjectItem, error) {
+	var valid []ProjectItem
+	var errs []error
+	for _, item := range items {
+		err := parseFieldValues(item)
+		if err == nil {
+			valid = append(valid, item)
+			continue
+		}
+		if ignore {
+			slog.Warn("Skipping item", "url", item.URL, "err", err)
+			continue
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", item.URL, err))
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("malformed field values (use --ignore-parse-errors to skip these items):\n%w", errors.Join(errs...))
+	}
+	return valid, nil
+}
+
+func parseFieldValues(item ProjectItem) error {
+	if item.BountyAmount != "" {
+		if _, err := parseBountyAmount(item.BountyAmount); err != nil {
+			return err
+		}
+	}
+	if item.DueDate != "" {
+		if _, err := parseDueDate(item.DueDate); err != nil {
+			return fmt.Errorf("due date %q is not a date", item.DueDate)
+		}
+	}
+	return nil
+}
+
+// Bounty amounts are plain decimals or use commas as thousands separators,
+// such as 1500.5 or 1,500.5. A comma anywhere else, as in the European
+// 12,50, is rejected rather than dropped.
+var (
+	plainAmountPattern   = regexp.MustCompile(`^[-+]?(\d+(\.\d*)?|\.\d+)$`)
+	groupedAmountPattern = regexp.MustCompile(`^[-+]?\d{1,3}(,\d{3})+(\.\d+)?$`)
+)
+
+// canonicalBountyAmount parses a bounty amount as typed into a project field
+// or pasted from a spreadsheet and returns it as a plain decimal string:
+// thousands separators are removed and a k or m suffix multiplies by a
+// thousand or a million, so "1,500", "1.5k" and "1500" are all "1500". The
+// conversion is exact, so the result can be normalized or formatted without
+// going through a float.
+func canonicalBountyAmount(s string) (string, error) {
+	number := strings.TrimSpace(s)
+	shift := 0
+	switch {
+	case strings.HasSuffix(number, "k"), strings.HasSuffix(number, "K"):
+		shift = 3
+	case strings.HasSuffix(number, "m"), strings.HasSuffix(number, "M"):
+		shift = 6
+	}
+	if shift > 0 {
+		number = number[:len(number)-1]
+	}
+	switch {
+	case plainAmountPattern.MatchString(number):
+	case groupedAmountPattern.MatchString(number):
+		number = strings.ReplaceAll(number, ",", "")
+	default:
+		return "", fmt.Errorf("bounty amount %q is not a number", s)
+	}
+	r, ok := new(big.Rat).SetString(number)
+	if !ok {
+		return "", fmt.Errorf("bounty amount %q is not a number", s)
+	}
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(shift)), nil)))
+	decimals := 0
+	if _, fraction, ok := strings.Cut(number, "."); ok {
+		decimals = max(len(fraction)-shift, 0)
+	}
+	return r.FloatString(decimals), nil
+}
+
+// parseBountyAmount parses a bounty amount with canonicalBountyAmount.
+func parseBountyAmount(s string) (float64, error) {
+	number, err := canonicalBountyAmount(s)
+	if err != nil {
+		return 0, err
+	}
+	amount, err := strconv.ParseFloat(number, 64)
+	if err != nil || math.IsInf(amount, 0) || math.IsNaN(amount) {
+		return 0, fmt.Errorf("bounty amount %q is not a number", s)
+	}
+	return amount, nil
+}
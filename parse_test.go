@@ -0,0 +1,48 @@
+package main
+
+import "testing"
+
+func TestCanonicalBountyAmount(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "1500", want: "1500"},
+		{in: " 1500.50 ", want: "1500.50"},
+		{in: "1,500", want: "1500"},
+		{in: "1,234,567.25", want: "1234567.25"},
+		{in: "1.5k", want: "1500"},
+		{in: "1.2345k", want: "1234.5"},
+		{in: "2M", want: "2000000"},
+		{in: "1,500k", want: "1500000"},
+		{in: "12,50", wantErr: true},
+		{in: "1,50,000", wantErr: true},
+		{in: ",500", wantErr: true},
+		{in: "1500,", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := canonicalBountyAmount(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("canonicalBountyAmount(%q) = %q, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("canonicalBountyAmount(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestNormalizeAmountAcceptsGroupedAndSuffixedAmounts(t *testing.T) {
+	decimals := map[string]int{"USDC": 6}
+	for in, want := range map[string]string{"1,500": "1500000000", "1.5k": "1500000000", "0.25": "250000"} {
+		got, err := normalizeAmount(in, "USDC", decimals)
+		if err != nil || got != want {
+			t.Errorf("normalizeAmount(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+}
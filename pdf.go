@@ -0,0 +1,113 @@
+//go:build pdf
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func init() {
+	taggedFormats["pdf"] = func(opts *options) outputFormat {
+		summaryOpts := summaryOptions{maxRecent: opts.summaryMaxRecent, symbol: opts.bountySymbol}
+		return outputFormat{
+			filename: "pending_payment_summary.pdf",
+			generate: func(items []ProjectItem, filename string) error {
+				return generatePDFReport(items, filename, summaryOpts)
+			},
+		}
+	}
+}
+
+// generatePDFReport writes the summary report as a PDF for record-keeping:
+// the same sections as the text report followed by a table of every item.
+// Like the text report, the Recent Activity section lists opts.maxRecent
+// items. The Go fonts are embedded so the file renders identically
+// everywhere.
+func generatePDFReport(items []ProjectItem, filename string, opts summaryOptions) error {
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes("Go", "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes("Go", "B", gobold.TTF)
+	pdf.SetTitle("Project Summary Report", true)
+	pdf.AddPage()
+
+	heading := func(text string) {
+		pdf.Ln(3)
+		pdf.SetFont("Go", "B", 13)
+		pdf.CellFormat(0, 8, text, "", 1, "L", false, 0, "")
+		pdf.SetFont("Go", "", 10)
+	}
+	line := func(text string) {
+		pdf.CellFormat(0, 6, text, "", 1, "L", false, 0, "")
+	}
+
+	pdf.SetFont("Go", "B", 18)
+	pdf.CellFormat(0, 10, "Project Summary Report", "", 1, "L", false, 0, "")
+	pdf.SetFont("Go", "", 10)
+	line("Generated on: " + time.Now().Format(time.RFC1123))
+
+	heading("Overview")
+	line(fmt.Sprintf("Total Items: %d", len(items)))
+	line(fmt.Sprintf("Total Bounty Value: %.0f %s", totalBounty(items), opts.symbol))
+
+	heading("Items by Recipient")
+	recipients := bountyByRecipient(items)
+	for _, recipient := range sortedKeys(recipients) {
+		line(fmt.Sprintf("- %s: %.0f %s", recipient, recipients[recipient], opts.symbol))
+	}
+
+	heading("Recent Activity")
+	for i, item := range items {
+		if opts.maxRecent >= 0 && i >= opts.maxRecent {
+			break
+		}
+		line(fmt.Sprintf("- %s (Updated: %s) - Recipient: %s, Bounty: %s %s",
+			truncateString(item.Title, 80),
+			item.UpdatedAt.Format("2006-01-02"),
+			item.Recipient,
+			item.BountyAmount,
+			item.BountySymbol,
+		))
+	}
+
+	heading("Items")
+	columns := []struct {
+		name  string
+		width float64
+	}{
+		{"Title", 105}, {"Recipient", 75}, {"Bounty", 32}, {"Updated", 25}, {"Org", 40},
+	}
+	pdf.SetFont("Go", "B", 9)
+	for _, col := range columns {
+		pdf.CellFormat(col.width, 7, col.name, "1", 0, "L", false, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetFont("Go", "", 9)
+	for _, item := range items {
+		values := []string{
+			truncateString(item.Title, 55),
+			truncateString(item.Recipient, 42),
+			item.BountyAmount + " " + item.BountySymbol,
+			item.UpdatedAt.Format("2006-01-02"),
+			item.Org,
+		}
+		for i, col := range columns {
+			pdf.CellFormat(col.width, 6, values[i], "1", 0, "L", false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	file, err := createOutput(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Abort()
+	if err := pdf.Output(file); err != nil {
+		return err
+	}
+	return file.Close()
+}
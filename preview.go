@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"golang.org/x/term"
+)
+
+// confirmExport shows items in a terminal table and asks whether to export
+// them. It returns true without prompting when stdout is not a terminal, so
+// --preview is a no-op in scripts and CI.
+func confirmExport(items []ProjectItem) bool {
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return true
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Title", "Recipient", "Bounty Amount", "Status"})
+	for _, item := range items {
+		t.AppendRow(table.Row{truncateString(item.Title, 50), item.Recipient, item.BountyAmount, item.Status})
+	}
+	t.Render()
+
+	fmt.Printf("Export %d items? [y/N] ", len(items))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateLimitTransport watches the X-RateLimit-Remaining and X-RateLimit-Reset
+// headers of GitHub responses. It warns once per rate-limit window when the
+// remaining points drop below threshold and, once they are used up, holds
+// further requests until the window resets, sending a request that was
+// rejected for the exhausted quota again afterwards.
+type rateLimitTransport struct {
+	next      http.RoundTripper
+	threshold int
+
+	mu sync.Mutex
+	// exhaustedUntil is the reset time of a used-up quota.
+	exhaustedUntil time.Time
+	// warnedReset is the reset time of the window already warned about.
+	warnedReset time.Time
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.waitForReset(req.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || !t.observe(resp) {
+		return resp, err
+	}
+
+	// GitHub answers 403 or 429 when the quota was already used up.
+	if (resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests) || req.GetBody == nil {
+		return resp, nil
+	}
+	if err := t.waitForReset(req.Context()); err != nil {
+		return resp, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+	retry := req.Clone(req.Context())
+	retry.Body = body
+	resp, err = t.next.RoundTrip(retry)
+	if err == nil {
+		t.observe(resp)
+	}
+	return resp, err
+}
+
+// observe records the rate-limit headers of resp and reports whether they
+// say the quota is used up.
+func (t *rateLimitTransport) observe(resp *http.Response) bool {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return false
+	}
+	resetUnix, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return false
+	}
+	reset := time.Unix(resetUnix, 0)
+	slog.Debug("GitHub rate limit", "remaining", remaining, "reset", reset)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if remaining < t.threshold && !reset.Equal(t.warnedReset) {
+		t.warnedReset = reset
+		slog.Warn("GitHub rate limit running low", "remaining", remaining, "threshold", t.threshold, "reset", reset)
+	}
+	if remaining > 0 {
+		return false
+	}
+	t.exhaustedUntil = reset
+	return true
+}
+
+// waitForReset blocks until the reset time of a used-up quota, if any, or
+// until ctx is done.
+func (t *rateLimitTransport) waitForReset(ctx context.Context) error {
+	t.mu.Lock()
+	until := t.exhaustedUntil
+	t.mu.Unlock()
+	wait := time.Until(until)
+	if wait <= 0 {
+		return nil
+	}
+	slog.Warn("GitHub rate limit exhausted; waiting for the reset", "wait", wait.Round(time.Second), "reset", until)
+	select {
+	case <-time.After(wait):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// urlCheckTimeout bounds each HEAD request made by --check-url-reachable.
+const urlCheckTimeout = 10 * time.Second
+
+// checkURLReachable sends a HEAD request to every item URL through
+// httpClient, the client of the GitHub requests, at most concurrency at a
+// time, and reports those that do not answer 200 OK. Deleted issues and
+// issues in repositories made private show up as 404. Draft items have no
+// URL and are skipped.
+func checkURLReachable(ctx context.Context, httpClient *http.Client, items []ProjectItem, concurrency int) []finding {
+	results := make([]string, len(items))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, item := range items {
+		if item.URL == "" {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				results[i] = fmt.Sprintf("%s: %v", item.URL, ctx.Err())
+				return
+			}
+			defer func() { <-sem }()
+			if err := headURL(ctx, httpClient, item.URL); err != nil {
+				results[i] = fmt.Sprintf("%s: %v", item.URL, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var findings []finding
+	for _, message := range results {
+		if message != "" {
+			findings = append(findings, finding{check: "url-reachable", message: message})
+		}
+	}
+	return findings
+}
+
+func headURL(ctx context.Context, httpClient *http.Client, url string) error {
+	ctx, cancel := context.WithTimeout(ctx, urlCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %s", resp.Status)
+	}
+	return nil
+}
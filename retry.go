@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// maxQueryRetries is the number of times a failed GraphQL query is retried,
+// set by --max-retries.
+var maxQueryRetries = 3
+
+// Backoff between retries doubles from retryBaseDelay up to retryMaxDelay.
+const (
+	retryBaseDelay = time.Second
+	retryMaxDelay  = 30 * time.Second
+)
+
+// queryWithRetry runs client.Query, retrying transient failures such as
+// GitHub's occasional 502s and timeouts up to maxRetries times with
+// exponential backoff. Other errors, like bad credentials or an invalid
+// query, are returned immediately.
+func queryWithRetry(ctx context.Context, client GraphQLClient, query any, variables map[string]any, maxRetries int) error {
+	delay := retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		err := client.Query(ctx, query, variables)
+		if err == nil || attempt >= maxRetries || ctx.Err() != nil || !isRetryable(err) {
+			return err
+		}
+		slog.Warn("GitHub query failed; retrying", "err", err, "delay", delay, "attempt", attempt+1, "max_retries", maxRetries)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delay = min(delay*2, retryMaxDelay)
+	}
+}
+
+// isRetryable reports whether err looks like a transient server or network
+// failure.
+func isRetryable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	msg := err.Error()
+	for _, s := range []string{"502", "503", "timeout"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
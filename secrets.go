@@ -0,0 +1,43 @@
+package main
+
+import (
+	"log/slog"
+	"regexp"
+)
+
+const redactedSecret = "[REDACTED]"
+
+// secretPatterns match credentials that contributors commonly paste into
+// issue titles and bodies by accident.
+var secretPatterns = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"GitHub token", regexp.MustCompile(`\b(ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36,}\b`)},
+	{"GitHub fine-grained token", regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{22,}\b`)},
+	{"private key", regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?(-----END [A-Z ]*PRIVATE KEY-----|$)`)},
+	{"AWS access key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}\b`)},
+}
+
+// redactSecrets replaces anything matching secretPatterns in the Title and
+// Description of item, logging a warning that names the item and the kind of
+// secret found. The matched values themselves are never logged.
+func redactSecrets(item *ProjectItem) {
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"title", &item.Title},
+		{"description", &item.Description},
+	}
+	for _, pattern := range secretPatterns {
+		for _, field := range fields {
+			if !pattern.re.MatchString(*field.value) {
+				continue
+			}
+			*field.value = pattern.re.ReplaceAllString(*field.value, redactedSecret)
+			slog.Warn("Redacted secret", "pattern", pattern.name, "field", field.name, "url", item.URL)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sepaOptions identifies the account the sepa-xml transfers are paid from.
+type sepaOptions struct {
+	debtorIBAN string
+	debtorName string
+}
+
+// The types below are the subset of ISO 20022 pain.001.001.03
+// (CustomerCreditTransferInitiation) needed for a single batch of SEPA
+// credit transfers.
+type sepaDocument struct {
+	XMLName    xml.Name       `xml:"urn:iso:std:iso:20022:tech:xsd:pain.001.001.03 Document"`
+	Initiation sepaInitiation `xml:"CstmrCdtTrfInitn"`
+}
+
+type sepaInitiation struct {
+	GroupHeader sepaGroupHeader `xml:"GrpHdr"`
+	PaymentInfo sepaPaymentInfo `xml:"PmtInf"`
+}
+
+type sepaGroupHeader struct {
+	MessageID        string    `xml:"MsgId"`
+	CreationDateTime string    `xml:"CreDtTm"`
+	NumberOfTxs      int       `xml:"NbOfTxs"`
+	ControlSum       string    `xml:"CtrlSum"`
+	InitiatingParty  sepaParty `xml:"InitgPty"`
+}
+
+type sepaPaymentInfo struct {
+	PaymentInfoID   string               `xml:"PmtInfId"`
+	PaymentMethod   string               `xml:"PmtMtd"`
+	NumberOfTxs     int                  `xml:"NbOfTxs"`
+	ControlSum      string               `xml:"CtrlSum"`
+	ServiceLevel    string               `xml:"PmtTpInf>SvcLvl>Cd"`
+	ExecutionDate   string               `xml:"ReqdExctnDt"`
+	Debtor          sepaParty            `xml:"Dbtr"`
+	DebtorIBAN      string               `xml:"DbtrAcct>Id>IBAN"`
+	DebtorAgent     string               `xml:"DbtrAgt>FinInstnId>Othr>Id"`
+	ChargeBearer    string               `xml:"ChrgBr"`
+	CreditTransfers []sepaCreditTransfer `xml:"CdtTrfTxInf"`
+}
+
+type sepaParty struct {
+	Name string `xml:"Nm"`
+}
+
+type sepaCreditTransfer struct {
+	EndToEndID   string     `xml:"PmtId>EndToEndId"`
+	Amount       sepaAmount `xml:"Amt>InstdAmt"`
+	Creditor     sepaParty  `xml:"Cdtr"`
+	CreditorIBAN string     `xml:"CdtrAcct>Id>IBAN"`
+	Remittance   string     `xml:"RmtInf>Ustrd"`
+}
+
+type sepaAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// generateSEPAXML writes a pain.001.001.03 credit transfer file with one
+// CdtTrfTxInf per item, paying BountyAmount EUR to the IBAN in Recipient.
+// Items without a valid IBAN or a positive amount are skipped with a
+// warning, since a bank rejects the whole file over a single bad entry.
+// Amounts with sub-cent precision are skipped with an error rather than
+// rounded.
+func generateSEPAXML(items []ProjectItem, filename string, opts sepaOptions) error {
+	now := time.Now()
+	var transfers []sepaCreditTransfer
+	var totalCents int64
+	for _, item := range items {
+		iban := normalizeIBAN(item.Recipient)
+		if !validIBAN(iban) {
+			slog.Warn("Skipping item in sepa-xml: recipient is not a valid IBAN", "url", item.URL, "recipient", item.Recipient)
+			continue
+		}
+		cents, err := parseCents(item.BountyAmount)
+		if errors.Is(err, errSubCent) {
+			slog.Error("Skipping item in sepa-xml: bounty amount has more than two decimals", "url", item.URL, "bounty_amount", item.BountyAmount)
+			continue
+		}
+		if err != nil || cents <= 0 {
+			slog.Warn("Skipping item in sepa-xml: bounty amount is not a positive number", "url", item.URL, "bounty_amount", item.BountyAmount)
+			continue
+		}
+		totalCents += cents
+		creditor := "NOTPROVIDED"
+		if len(item.AssignedTo) > 0 {
+			creditor = item.AssignedTo[0]
+		}
+		transfers = append(transfers, sepaCreditTransfer{
+			EndToEndID:   fmt.Sprintf("BUIDL-%d", len(transfers)+1),
+			Amount:       sepaAmount{Currency: "EUR", Value: formatCents(cents)},
+			Creditor:     sepaParty{Name: truncateRunes(creditor, 70)},
+			CreditorIBAN: iban,
+			Remittance:   truncateRunes(item.Title+" "+item.URL, 140),
+		})
+	}
+	if len(transfers) == 0 {
+		return fmt.Errorf("no items have a valid IBAN recipient and amount")
+	}
+
+	messageID := "BUIDL-" + now.UTC().Format(snapshotTimeFormat)
+	debtor := sepaParty{Name: truncateRunes(opts.debtorName, 70)}
+	doc := sepaDocument{Initiation: sepaInitiation{
+		GroupHeader: sepaGroupHeader{
+			MessageID:        messageID,
+			CreationDateTime: now.Format("2006-01-02T15:04:05"),
+			NumberOfTxs:      len(transfers),
+			ControlSum:       formatCents(totalCents),
+			InitiatingParty:  debtor,
+		},
+		PaymentInfo: sepaPaymentInfo{
+			PaymentInfoID:   messageID,
+			PaymentMethod:   "TRF",
+			NumberOfTxs:     len(transfers),
+			ControlSum:      formatCents(totalCents),
+			ServiceLevel:    "SEPA",
+			ExecutionDate:   now.Format("2006-01-02"),
+			Debtor:          debtor,
+			DebtorIBAN:      normalizeIBAN(opts.debtorIBAN),
+			DebtorAgent:     "NOTPROVIDED",
+			ChargeBearer:    "SLEV",
+			CreditTransfers: transfers,
+		},
+	}}
+
+	file, err := createOutput(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Abort()
+
+	if _, err := io.WriteString(file, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(file)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(file, "\n"); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// normalizeIBAN removes spaces from an IBAN and upper-cases it.
+func normalizeIBAN(s string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(s), " ", ""))
+}
+
+// validIBAN reports whether iban, normalized with normalizeIBAN, has a valid
+// ISO 13616 mod-97 check sum.
+func validIBAN(iban string) bool {
+	expected, ok := ibanCheckDigits(iban)
+	return ok && expected == iban[2:4]
+}
+
+// ibanCheckDigits computes the check digits iban should have at positions 3
+// and 4. ok is false if iban is too short or long, or contains characters
+// other than digits and upper-case letters.
+func ibanCheckDigits(iban string) (expected string, ok bool) {
+	if len(iban) < 15 || len(iban) > 34 {
+		return "", false
+	}
+	var digits strings.Builder
+	for _, r := range iban[4:] + iban[:2] + "00" {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			digits.WriteString(strconv.Itoa(int(r-'A') + 10))
+		default:
+			return "", false
+		}
+	}
+	n, _ := new(big.Int).SetString(digits.String(), 10)
+	return fmt.Sprintf("%02d", 98-new(big.Int).Mod(n, big.NewInt(97)).Int64()), true
+}
+
+// errSubCent is returned by parseCents for amounts that are not a whole
+// number of cents.
+var errSubCent = errors.New("amount has more than two decimals")
+
+// parseCents converts a bounty amount to cents exactly. Amounts with
+// sub-cent precision are rejected with errSubCent instead of being rounded,
+// so the transfer file never pays a different amount than the project
+// shows.
+func parseCents(amount string) (int64, error) {
+	canonical, err := canonicalBountyAmount(amount)
+	if err != nil {
+		return 0, err
+	}
+	r, ok := new(big.Rat).SetString(canonical)
+	if !ok {
+		return 0, fmt.Errorf("bounty amount %q is not a number", amount)
+	}
+	r.Mul(r, big.NewRat(100, 1))
+	if !r.IsInt() {
+		return 0, errSubCent
+	}
+	if !r.Num().IsInt64() {
+		return 0, fmt.Errorf("bounty amount %q is too large", amount)
+	}
+	return r.Num().Int64(), nil
+}
+
+// formatCents formats an amount in cents as a decimal with two places.
+func formatCents(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
+
+// truncateRunes shortens s to at most n characters to respect the maximum
+// lengths of pain.001 text fields.
+func truncateRunes(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
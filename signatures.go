@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"gopkg.in/yaml.v3"
+)
+
+// verifySignatures sets SignatureValid on each item whose description
+// carries a valid PGP signature. The signature is an ASCII-armored detached
+// signature in the signature field of the description's YAML front matter,
+// made over the rest of the description (everything after the closing
+// "---" line). It is checked against the GPG keys that the item's first
+// assignee has registered on GitHub, read from the REST API at restURL.
+func verifySignatures(ctx context.Context, httpClient *http.Client, restURL string, items []ProjectItem) {
+	keyrings := make(map[string]openpgp.EntityList)
+	for i := range items {
+		item := &items[i]
+		item.SignatureValid = false
+
+		signature, message, err := parseSignedDescription(item.Description)
+		if err != nil {
+			slog.Warn("Cannot verify signature", "url", item.URL, "err", err)
+			continue
+		}
+		if len(item.AssignedTo) == 0 {
+			slog.Warn("Signed item has no assignee to verify against", "url", item.URL)
+			continue
+		}
+
+		login := item.AssignedTo[0]
+		keyring, ok := keyrings[login]
+		if !ok {
+			if keyring, err = fetchGPGKeys(ctx, httpClient, restURL, login); err != nil {
+				slog.Warn("Fetching GPG keys failed", "url", item.URL, "login", login, "err", err)
+				continue
+			}
+			keyrings[login] = keyring
+		}
+
+		if _, err := openpgp.CheckArmoredDetachedSignature(keyring, strings.NewReader(message), strings.NewReader(signature), nil); err != nil {
+			slog.Warn("Signature does not verify against the assignee's GPG keys", "url", item.URL, "login", login, "err", err)
+			continue
+		}
+		item.SignatureValid = true
+	}
+}
+
+// parseSignedDescription splits a description of the form
+//
+//	---
+//	signature: |
+//	  -----BEGIN PGP SIGNATURE-----
+//	  ...
+//	---
+//	<signed message>
+//
+// into the armored signature and the signed message.
+func parseSignedDescription(description string) (signature, message string, err error) {
+	description = strings.ReplaceAll(description, "\r\n", "\n")
+	rest, ok := strings.CutPrefix(description, "---\n")
+	if !ok {
+		return "", "", errors.New("description has no front matter")
+	}
+	frontMatter, message, ok := strings.Cut(rest, "\n---\n")
+	if !ok {
+		return "", "", errors.New("front matter is not terminated by ---")
+	}
+
+	var fields struct {
+		Signature string `yaml:"signature"`
+	}
+	if err := yaml.Unmarshal([]byte(frontMatter), &fields); err != nil {
+		return "", "", fmt.Errorf("parsing front matter: %w", err)
+	}
+	if fields.Signature == "" {
+		return "", "", errors.New("front matter has no signature field")
+	}
+	return fields.Signature, message, nil
+}
+
+// fetchGPGKeys returns the public GPG keys login has added to their GitHub
+// account, from the REST API at restURL.
+func fetchGPGKeys(ctx context.Context, httpClient *http.Client, restURL, login string) (openpgp.EntityList, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, restURL+"/users/"+url.PathEscape(login)+"/gpg_keys", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub returned HTTP %d", resp.StatusCode)
+	}
+
+	var keys []struct {
+		RawKey string `json:"raw_key"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&keys); err != nil {
+		return nil, err
+	}
+
+	var keyring openpgp.EntityList
+	for _, key := range keys {
+		entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key.RawKey))
+		if err != nil {
+			continue
+		}
+		keyring = append(keyring, entities...)
+	}
+	if len(keyring) == 0 {
+		return nil, errors.New("no usable GPG keys")
+	}
+	return keyring, nil
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchGPGKeysUsesRESTURL(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte("[]"))
+	}))
+	defer srv.Close()
+
+	// No keys are registered, so the lookup fails after reaching the server.
+	if _, err := fetchGPGKeys(context.Background(), srv.Client(), srv.URL+"/api/v3", "octocat"); err == nil {
+		t.Error("fetchGPGKeys succeeded without keys")
+	}
+	if path != "/api/v3/users/octocat/gpg_keys" {
+		t.Errorf("requested %q, want the GPG keys under the REST URL", path)
+	}
+}
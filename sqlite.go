@@ -0,0 +1,197 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// --sqlite-mode values.
+const (
+	sqliteOverwrite = "overwrite"
+	sqliteAppend    = "append"
+)
+
+// exportedAtFormat is RFC 3339 with a fixed nine fractional digits, so two
+// exports appended within the same second stay apart and MAX(exported_at)
+// still sorts them correctly as text.
+const exportedAtFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+// sqliteSchema creates the project_items table, with one column per
+// ProjectItem field plus the time of the export, and the index used to
+// aggregate bounties by recipient. Bounty amounts are stored as text, so
+// they read back exactly (1500.50 stays 1500.50); SQLite's SUM still
+// treats them as numbers.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS project_items (
+	id              TEXT NOT NULL,
+	title           TEXT,
+	url             TEXT,
+	created_at      TEXT,
+	updated_at      TEXT,
+	due_date        TEXT,
+	created_by      TEXT,
+	assigned_to     TEXT,
+	labels          TEXT,
+	description     TEXT,
+	recipient       TEXT,
+	bounty_amount   TEXT,
+	bounty_symbol   TEXT,
+	org             TEXT,
+	project_number  INTEGER,
+	status          TEXT,
+	sprint          TEXT,
+	content_type    TEXT,
+	signature_valid INTEGER,
+	exported_at     TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS project_items_recipient_bounty ON project_items (recipient, bounty_amount);
+`
+
+// generateSQLite writes the items to the project_items table of the SQLite
+// database filename, for ad-hoc queries such as
+//
+//	SELECT recipient, SUM(bounty_amount) FROM project_items GROUP BY recipient;
+//
+// In overwrite mode an existing table is dropped first; in append mode the
+// items are added to it, so repeated runs build up a history. Unlike the
+// other formats the file is not written through createOutput: the drop and
+// all inserts run in a single transaction instead, so an interrupted run
+// rolls back to the previous contents. The database can be read back with
+// --from-cache. Assignees and labels are joined with
+// ';' as in the CSV and timestamps are RFC 3339.
+func generateSQLite(items []ProjectItem, filename, mode string) error {
+	// A dry run builds the database in memory, so the export is still
+	// exercised without creating the file.
+	dsn := filename
+	if dryRun {
+		dsn = ":memory:"
+	}
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if mode == sqliteOverwrite {
+		if _, err := tx.Exec("DROP TABLE IF EXISTS project_items"); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("creating table: %w", err)
+	}
+
+	insert, err := tx.Prepare(`INSERT INTO project_items VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer insert.Close()
+	exportedAt := time.Now().UTC().Format(exportedAtFormat)
+	for _, item := range items {
+		_, err := insert.Exec(
+			item.ID,
+			item.Title,
+			item.URL,
+			item.CreatedAt.Format(time.RFC3339),
+			item.UpdatedAt.Format(time.RFC3339),
+			item.DueDate,
+			item.CreatedBy,
+			strings.Join(item.AssignedTo, ";"),
+			strings.Join(item.Labels, ";"),
+			item.Description,
+			item.Recipient,
+			item.BountyAmount,
+			item.BountySymbol,
+			item.Org,
+			item.ProjectNumber,
+			item.Status,
+			item.Sprint,
+			item.ContentType,
+			item.SignatureValid,
+			exportedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("inserting %s: %w", item.URL, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// loadSQLiteItems reads back the items of the most recent export in the
+// sqlite database filename, for --from-cache. In append mode the database
+// holds several exports; only the latest is returned. It fails if that
+// export is older than maxAge, unless maxAge is zero.
+func loadSQLiteItems(filename string, maxAge time.Duration) ([]ProjectItem, time.Time, error) {
+	// Opening a missing file would silently create an empty database.
+	if _, err := os.Stat(filename); err != nil {
+		return nil, time.Time{}, err
+	}
+	db, err := sql.Open("sqlite", filename)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer db.Close()
+
+	var latest sql.NullString
+	if err := db.QueryRow("SELECT MAX(exported_at) FROM project_items").Scan(&latest); err != nil {
+		return nil, time.Time{}, err
+	}
+	if !latest.Valid {
+		return nil, time.Time{}, fmt.Errorf("%s holds no exported items", filename)
+	}
+	exportedAt, err := time.Parse(time.RFC3339, latest.String)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid exported_at %q: %w", latest.String, err)
+	}
+	if age := time.Since(exportedAt); maxAge > 0 && age > maxAge {
+		return nil, exportedAt, fmt.Errorf("cached export from %s is %v old, more than --cache-max-age %v", exportedAt.Format(time.RFC3339), age.Round(time.Second), maxAge)
+	}
+
+	rows, err := db.Query(`SELECT id, title, url, created_at, updated_at, due_date, created_by, assigned_to, labels, description,
+		recipient, bounty_amount, bounty_symbol, org, project_number, status, sprint, content_type, signature_valid
+		FROM project_items WHERE exported_at = ? ORDER BY rowid`, latest.String)
+	if err != nil {
+		return nil, exportedAt, err
+	}
+	defer rows.Close()
+
+	var items []ProjectItem
+	for rows.Next() {
+		var item ProjectItem
+		var createdAt, updatedAt, assignedTo, labels string
+		err := rows.Scan(&item.ID, &item.Title, &item.URL, &createdAt, &updatedAt, &item.DueDate, &item.CreatedBy, &assignedTo, &labels, &item.Description,
+			&item.Recipient, &item.BountyAmount, &item.BountySymbol, &item.Org, &item.ProjectNumber, &item.Status, &item.Sprint, &item.ContentType, &item.SignatureValid)
+		if err != nil {
+			return nil, exportedAt, err
+		}
+		if item.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+			return nil, exportedAt, fmt.Errorf("%s: invalid created_at: %w", item.URL, err)
+		}
+		if item.UpdatedAt, err = time.Parse(time.RFC3339, updatedAt); err != nil {
+			return nil, exportedAt, fmt.Errorf("%s: invalid updated_at: %w", item.URL, err)
+		}
+		item.AssignedTo = splitJoined(assignedTo)
+		item.Labels = splitJoined(labels)
+		items = append(items, item)
+	}
+	return items, exportedAt, rows.Err()
+}
+
+// splitJoined reverses strings.Join(list, ";"), mapping "" to a nil list.
+func splitJoined(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ";")
+}
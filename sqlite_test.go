@@ -0,0 +1,27 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadSQLiteItemsKeepsExportsInTheSameSecondApart(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cache.db")
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	first := []ProjectItem{{ID: "1", Title: "First", CreatedAt: createdAt, UpdatedAt: createdAt}}
+	second := []ProjectItem{{ID: "2", Title: "Second", CreatedAt: createdAt, UpdatedAt: createdAt}}
+	for _, items := range [][]ProjectItem{first, second} {
+		if err := generateSQLite(items, filename, sqliteAppend); err != nil {
+			t.Fatalf("generateSQLite: %v", err)
+		}
+	}
+
+	items, _, err := loadSQLiteItems(filename, 0)
+	if err != nil {
+		t.Fatalf("loadSQLiteItems: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != "2" {
+		t.Errorf("loadSQLiteItems returned %+v, want only the item of the latest export", items)
+	}
+}
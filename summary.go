@@ -0,0 +1,224 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+// Summary table formats accepted by --summary-table-format.
+const (
+	summaryTablePlain    = "plain"
+	summaryTableMarkdown = "markdown-table"
+	summaryTableASCII    = "ascii-table"
+)
+
+// summaryOptions controls the layout of the text summary report.
+type summaryOptions struct {
+	// tableFormat selects how the "Items by Recipient" section is rendered.
+	tableFormat string
+	// maxRecent limits the "Recent Activity" section; -1 lists every item.
+	maxRecent int
+	// recipientCap is the most a recipient may be paid in capPeriod; 0
+	// disables the "Capped Recipients" section.
+	recipientCap float64
+	capPeriod    string
+	// symbol is the --bounty-symbol the totals are given in.
+	symbol string
+}
+
+func generateSummaryReport(items []ProjectItem, filename string, opts summaryOptions) error {
+	file, err := createOutput(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Abort()
+
+	// Write summary
+	fmt.Fprintf(file, "# Project Summary Report\n")
+	fmt.Fprintf(file, "Generated on: %s\n\n", time.Now().Format(time.RFC1123))
+
+	fmt.Fprintf(file, "## Overview\n")
+	fmt.Fprintf(file, "Total Items: %d\n", len(items))
+	unparsed := ""
+	for _, item := range items {
+		if item.BountyAmount == "" {
+			continue
+		}
+		if _, err := parseBountyAmount(item.BountyAmount); err != nil {
+			slog.Warn("Leaving bounty amount out of the summary total", "url", item.URL, "err", err)
+			unparsed = " (some amounts could not be parsed)"
+		}
+	}
+	fmt.Fprintf(file, "Total Bounty Value: %.0f %s%s\n\n", totalBounty(items), opts.symbol, unparsed)
+
+	// A combined export of several projects, from --orgs or repeated --org,
+	// also gets each project's subtotal.
+	if projects := projectTotals(items); len(projects) > 1 {
+		fmt.Fprintf(file, "## Items by Project\n")
+		for _, p := range projects {
+			fmt.Fprintf(file, "- %s #%d: %d items, %.0f %s\n", p.org, p.number, p.items, p.bounty, opts.symbol)
+		}
+		fmt.Fprintf(file, "\n")
+	}
+
+	fmt.Fprintf(file, "## Items by Recipient\n")
+	writeRecipientTable(file, bountyByRecipient(items), opts.tableFormat, opts.symbol)
+	fmt.Fprintf(file, "\n")
+
+	if opts.recipientCap > 0 {
+		capped := cappedRecipients(items, opts.recipientCap, opts.capPeriod, time.Now())
+		fmt.Fprintf(file, "## Capped Recipients\n")
+		if len(capped) == 0 {
+			fmt.Fprintf(file, "No recipient exceeds the cap of %.0f %s per %s.\n", opts.recipientCap, opts.symbol, opts.capPeriod)
+		}
+		for _, c := range capped {
+			slog.Warn("Recipient exceeds --recipient-cap", "recipient", c.recipient, "total", c.total, "cap", opts.recipientCap, "cap_period", opts.capPeriod)
+			fmt.Fprintf(file, "- %s: %.0f %s (%.0f over the cap)\n", c.recipient, c.total, opts.symbol, c.overage)
+		}
+		fmt.Fprintf(file, "\n")
+	}
+
+	if missing := missingRecipients(items); len(missing) > 0 {
+		fmt.Fprintf(file, "## Missing Recipient\n")
+		for _, item := range missing {
+			fmt.Fprintf(file, "- %s (%s)\n", item.Title, item.URL)
+		}
+		fmt.Fprintf(file, "\n")
+	}
+
+	fmt.Fprintf(file, "## Recent Activity\n")
+	count := 0
+	for _, item := range items {
+		if opts.maxRecent >= 0 && count >= opts.maxRecent {
+			break
+		}
+		fmt.Fprintf(file, "- %s (Updated: %s) - Recipient: %s, Bounty: %s %s\n",
+			item.Title,
+			item.UpdatedAt.Format("2006-01-02"),
+			item.Recipient,
+			item.BountyAmount,
+			item.BountySymbol,
+		)
+		count++
+	}
+
+	return file.Close()
+}
+
+// writeRecipientTable renders per-recipient totals, in symbol, in the given
+// summary table format.
+func writeRecipientTable(w io.Writer, recipients map[string]float64, format, symbol string) {
+	switch format {
+	case summaryTableMarkdown:
+		fmt.Fprintf(w, "| Recipient | Total (%s) |\n|---|---:|\n", symbol)
+		for _, recipient := range sortedKeys(recipients) {
+			fmt.Fprintf(w, "| %s | %.0f |\n", escapeMarkdownCell(recipient), recipients[recipient])
+		}
+	case summaryTableASCII:
+		t := table.NewWriter()
+		t.SetOutputMirror(w)
+		t.AppendHeader(table.Row{"Recipient", "Total (" + symbol + ")"})
+		t.SetColumnConfigs([]table.ColumnConfig{{Number: 2, Align: text.AlignRight}})
+		for _, recipient := range sortedKeys(recipients) {
+			t.AppendRow(table.Row{recipient, fmt.Sprintf("%.0f", recipients[recipient])})
+		}
+		t.Render()
+	default:
+		for recipient, amount := range recipients {
+			fmt.Fprintf(w, "- %s: %.0f %s\n", recipient, amount, symbol)
+		}
+	}
+}
+
+// totalBounty sums the bounty amounts of items. Amounts that cannot be
+// parsed count as 0.
+func totalBounty(items []ProjectItem) float64 {
+	total := 0.0
+	for _, item := range items {
+		if item.BountyAmount != "" {
+			bountyValue, _ := parseBountyAmount(item.BountyAmount)
+			total += bountyValue
+		}
+	}
+	return total
+}
+
+// bountyByRecipient sums the bounty amounts of items per recipient. Items
+// without a recipient are left out.
+func bountyByRecipient(items []ProjectItem) map[string]float64 {
+	recipientMap := make(map[string]float64)
+	for _, item := range items {
+		if item.Recipient != "" {
+			bountyValue, _ := parseBountyAmount(item.BountyAmount)
+			recipientMap[item.Recipient] += bountyValue
+		}
+	}
+	return recipientMap
+}
+
+// errMissingRecipients is returned by run, after the output files are
+// written, when items have no recipient. main exits with status 2 for it so
+// CI pipelines can tell these items apart from failed exports.
+var errMissingRecipients = errors.New("items without a recipient cannot be paid")
+
+// missingRecipients returns the items whose Recipient field is empty.
+func missingRecipients(items []ProjectItem) []ProjectItem {
+	var missing []ProjectItem
+	for _, item := range items {
+		if strings.TrimSpace(item.Recipient) == "" {
+			missing = append(missing, item)
+		}
+	}
+	return missing
+}
+
+// projectTotal is the item count and summed bounty of one project.
+type projectTotal struct {
+	projectRef
+	items  int
+	bounty float64
+}
+
+// projectTotals returns the totals of every project the items came from,
+// in the order the projects first appear.
+func projectTotals(items []ProjectItem) []projectTotal {
+	var totals []projectTotal
+	index := make(map[projectRef]int)
+	for _, item := range items {
+		ref := projectRef{org: item.Org, number: item.ProjectNumber}
+		i, ok := index[ref]
+		if !ok {
+			i = len(totals)
+			index[ref] = i
+			totals = append(totals, projectTotal{projectRef: ref})
+		}
+		totals[i].items++
+		totals[i].bounty += totalBounty([]ProjectItem{item})
+	}
+	return totals
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func truncateString(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen] + "..."
+}
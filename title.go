@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// titleData is the data passed to the --item-title-template template.
+type titleData struct {
+	Index        int // 1-based position of the item in the export
+	Title        string
+	BountyAmount string
+	Recipient    string
+}
+
+// applyTitleTemplate rewrites each item's Title using tmpl. Only the
+// exported copy changes; the GitHub issue itself is left untouched.
+func applyTitleTemplate(items []ProjectItem, tmpl *template.Template) error {
+	for i := range items {
+		var b strings.Builder
+		data := titleData{
+			Index:        i + 1,
+			Title:        items[i].Title,
+			BountyAmount: items[i].BountyAmount,
+			Recipient:    items[i].Recipient,
+		}
+		if err := tmpl.Execute(&b, data); err != nil {
+			return fmt.Errorf("error applying --item-title-template to %s: %w", items[i].URL, err)
+		}
+		items[i].Title = b.String()
+	}
+	return nil
+}
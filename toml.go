@@ -0,0 +1,25 @@
+package main
+
+import (
+	"github.com/BurntSushi/toml"
+)
+
+// tomlExport is the top-level TOML document: every item becomes one
+// [[items]] table, so the file decodes back into a tomlExport unchanged.
+type tomlExport struct {
+	Items []ProjectItem `toml:"items"`
+}
+
+// generateTOML writes the items as a TOML array of tables.
+func generateTOML(items []ProjectItem, filename string) error {
+	file, err := createOutput(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Abort()
+
+	if err := toml.NewEncoder(file).Encode(tomlExport{Items: items}); err != nil {
+		return err
+	}
+	return file.Close()
+}
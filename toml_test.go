@@ -0,0 +1,52 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+func TestGenerateTOMLRoundTrips(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "items.toml")
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	items := []ProjectItem{
+		{
+			ID:            "PVTI_1",
+			Title:         `Fix "quoted" titles`,
+			URL:           "https://github.com/NautilusOSS/repo/issues/1",
+			CreatedAt:     createdAt,
+			UpdatedAt:     createdAt.Add(time.Hour),
+			CreatedBy:     "alice",
+			AssignedTo:    []string{"alice", "bob"},
+			Labels:        []string{"bug", `say "hi"`},
+			Description:   "First line\nSecond line with a \\ backslash\n",
+			Recipient:     "0xabc",
+			BountyAmount:  "1500.50",
+			BountySymbol:  "BUIDL",
+			Org:           "NautilusOSS",
+			ProjectNumber: 1,
+			Status:        "Pending Payment",
+			ContentType:   contentTypeIssue,
+		},
+		{
+			ID:          "PVTI_2",
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt,
+			ContentType: contentTypeDraftIssue,
+		},
+	}
+	if err := generateTOML(items, filename); err != nil {
+		t.Fatalf("generateTOML: %v", err)
+	}
+
+	var decoded tomlExport
+	if _, err := toml.DecodeFile(filename, &decoded); err != nil {
+		t.Fatalf("decoding the TOML back: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Items, items) {
+		t.Errorf("decoded items differ:\ngot  %+v\nwant %+v", decoded.Items, items)
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// ERC-20 function selectors (first four bytes of the Keccak-256 of the
+// signature).
+const (
+	selectorBalanceOf = "0x70a08231" // balanceOf(address)
+	selectorDecimals  = "0x313ce567" // decimals()
+)
+
+var ethAddressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// checkWalletBalance verifies that payerAddress holds at least totalAmount
+// (in whole tokens, e.g. "1500.5") of the ERC-20 token at tokenAddress. It
+// talks to the node at rpcURL with plain JSON-RPC eth_call requests rather
+// than pulling in a full Ethereum client.
+func checkWalletBalance(ctx context.Context, rpcURL, tokenAddress, payerAddress, totalAmount string) error {
+	if !ethAddressPattern.MatchString(tokenAddress) {
+		return fmt.Errorf("invalid token address %q", tokenAddress)
+	}
+	if !ethAddressPattern.MatchString(payerAddress) {
+		return fmt.Errorf("invalid payer address %q", payerAddress)
+	}
+	required, ok := new(big.Rat).SetString(totalAmount)
+	if !ok {
+		return fmt.Errorf("invalid total amount %q", totalAmount)
+	}
+
+	decimals, err := ethCall(ctx, rpcURL, tokenAddress, selectorDecimals)
+	if err != nil {
+		return fmt.Errorf("reading token decimals: %w", err)
+	}
+	// balanceOf takes the address left-padded to a 32-byte word.
+	balance, err := ethCall(ctx, rpcURL, tokenAddress, selectorBalanceOf+strings.Repeat("0", 24)+strings.ToLower(payerAddress[2:]))
+	if err != nil {
+		return fmt.Errorf("reading payer balance: %w", err)
+	}
+
+	unit := new(big.Int).Exp(big.NewInt(10), decimals, nil)
+	available := new(big.Rat).SetFrac(balance, unit)
+	if available.Cmp(required) < 0 {
+		places := int(decimals.Int64())
+		return fmt.Errorf("payer %s holds %s tokens but the batch needs %s",
+			payerAddress, formatTokenAmount(available, places), formatTokenAmount(required, places))
+	}
+	return nil
+}
+
+// formatTokenAmount formats r with up to places decimal places, dropping
+// trailing zeros.
+func formatTokenAmount(r *big.Rat, places int) string {
+	s := r.FloatString(places)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// ethCall performs a read-only contract call and returns the result as an
+// unsigned integer.
+func ethCall(ctx context.Context, rpcURL, to, data string) (*big.Int, error) {
+	raw, err := ethRPC(ctx, rpcURL, "eth_call", map[string]string{"to": to, "data": data}, "latest")
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("empty result; is %s an ERC-20 contract?", to)
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
+
+// ethRPC sends a JSON-RPC request to rpcURL and returns the hex-encoded
+// result decoded to bytes.
+func ethRPC(ctx context.Context, rpcURL, method string, params ...interface{}) ([]byte, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rpcURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("RPC returned HTTP %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Result string
+		Error  *struct {
+			Code    int
+			Message string
+		}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("RPC error %d: %s", result.Error.Code, result.Error.Message)
+	}
+
+	// Quantities such as eth_chainId may have an odd number of hex digits.
+	digits := strings.TrimPrefix(result.Result, "0x")
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+	raw, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil, fmt.Errorf("decoding RPC result: %w", err)
+	}
+	return raw, nil
+}
+
+// knownChains are the networks --check-payment-token-config recognizes.
+var knownChains = map[int64]string{
+	1:     "Ethereum mainnet",
+	10:    "Optimism",
+	137:   "Polygon",
+	42161: "Arbitrum One",
+}
+
+// checkPaymentTokenConfig validates --token-address and --chain-id before a
+// run. A malformed or zero token address is an error; an unknown chain ID
+// only warns. When rpcURL is set it also confirms the node serves chainID and
+// that a contract is deployed at tokenAddress on it.
+func checkPaymentTokenConfig(ctx context.Context, rpcURL, tokenAddress string, chainID int64) error {
+	if !ethAddressPattern.MatchString(tokenAddress) {
+		return fmt.Errorf("invalid token address %q", tokenAddress)
+	}
+	if strings.Trim(tokenAddress[2:], "0") == "" {
+		return fmt.Errorf("token address must not be the zero address")
+	}
+	if chainID <= 0 {
+		return fmt.Errorf("--chain-id is required")
+	}
+	if _, ok := knownChains[chainID]; !ok {
+		slog.Warn("Chain ID is not a known network (1, 10, 137, 42161)", "chain_id", chainID)
+	}
+
+	if rpcURL == "" {
+		return nil
+	}
+	raw, err := ethRPC(ctx, rpcURL, "eth_chainId")
+	if err != nil {
+		return fmt.Errorf("reading chain ID from %s: %w", rpcURL, err)
+	}
+	if served := new(big.Int).SetBytes(raw); served.Int64() != chainID {
+		return fmt.Errorf("--ethereum-rpc serves chain %s, but --chain-id is %d", served, chainID)
+	}
+	code, err := ethRPC(ctx, rpcURL, "eth_getCode", tokenAddress, "latest")
+	if err != nil {
+		return fmt.Errorf("reading token contract code: %w", err)
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("no contract is deployed at %s on chain %d", tokenAddress, chainID)
+	}
+	return nil
+}
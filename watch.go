@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// snapshotTimeFormat is the UTC timestamp embedded in --watch-output-dir
+// file names, e.g. pending_payment_tasks_20240101T120000Z.csv.
+const snapshotTimeFormat = "20060102T150405Z"
+
+// watch runs the export every opts.watch until ctx is cancelled. A failed
+// cycle is logged and retried on the next tick rather than ending the watch.
+// When the item count moves by more than --watch-alert-threshold between two
+// successful cycles, an alert is logged and sent to --webhook-url.
+func watch(ctx context.Context, client GraphQLClient, httpClient *http.Client, opts *options) {
+	if opts.watchOutputDir != "" && !dryRun {
+		if err := os.MkdirAll(opts.watchOutputDir, 0o755); err != nil {
+			fatal("Error creating --watch-output-dir", "err", err)
+		}
+	}
+
+	ticker := time.NewTicker(opts.watch)
+	defer ticker.Stop()
+
+	previous := -1
+	for {
+		count, err := run(ctx, client, httpClient, opts)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("Watch cycle failed", "err", err)
+		}
+		// Missing recipients are reported, but the export itself succeeded.
+		if err == nil || errors.Is(err, errMissingRecipients) {
+			if previous >= 0 && opts.watchAlertThreshold > 0 {
+				alertOnCountChange(ctx, previous, count, opts)
+			}
+			previous = count
+		}
+		select {
+		case <-ctx.Done():
+			slog.Info("Stopping watch", "reason", ctx.Err())
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// alertOnCountChange logs and, if --webhook-url is set, posts an alert when
+// the item count changed by more than --watch-alert-threshold.
+func alertOnCountChange(ctx context.Context, previous, current int, opts *options) {
+	if diff := current - previous; diff <= opts.watchAlertThreshold && -diff <= opts.watchAlertThreshold {
+		return
+	}
+	payload := webhookPayload{
+		Text:          fmt.Sprintf("buidl-tools: item count changed from %d to %d (threshold %d)", previous, current, opts.watchAlertThreshold),
+		PreviousCount: previous,
+		CurrentCount:  current,
+	}
+	slog.Warn("Item count changed", "previous_count", previous, "item_count", current, "threshold", opts.watchAlertThreshold)
+	if opts.webhookURL == "" {
+		return
+	}
+	if err := sendWebhook(ctx, opts.webhookURL, payload); err != nil {
+		slog.Warn("Sending webhook notification failed", "err", err)
+	}
+}
+
+// outputPath returns the path a run should write filename to. Outside of
+// --watch-output-dir this is filename inside --output-dir, if set; otherwise
+// it is a timestamped snapshot inside the watch directory, so each cycle
+// adds to a historical archive instead of overwriting the previous one.
+// Absolute filenames, such as $GITHUB_STEP_SUMMARY, are left alone.
+func outputPath(filename string, opts *options, now time.Time) string {
+	if filepath.IsAbs(filename) {
+		return filename
+	}
+	if opts.watchOutputDir == "" {
+		return filepath.Join(opts.outputDir, filename)
+	}
+	ext := filepath.Ext(filename)
+	base := strings.TrimSuffix(filename, ext)
+	return filepath.Join(opts.watchOutputDir, base+"_"+now.UTC().Format(snapshotTimeFormat)+ext)
+}
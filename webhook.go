@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// webhookTimeout bounds a single --webhook-url notification.
+const webhookTimeout = 10 * time.Second
+
+// webhookPayload is the JSON body posted to --webhook-url. Text makes it
+// usable as a Slack or Mattermost incoming webhook as is.
+type webhookPayload struct {
+	Text          string `json:"text"`
+	PreviousCount int    `json:"previous_count"`
+	CurrentCount  int    `json:"current_count"`
+}
+
+// sendWebhook posts payload as JSON to url.
+func sendWebhook(ctx context.Context, url string, payload webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned HTTP %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// xmlExport is the root element of the xml output.
+type xmlExport struct {
+	XMLName xml.Name      `xml:"PendingPayments"`
+	Items   []ProjectItem `xml:"Item"`
+}
+
+// generateXML writes the items as an indented
+// <PendingPayments><Item>...</Item></PendingPayments> document for
+// enterprise import tools. Timestamps are written in RFC 3339.
+func generateXML(items []ProjectItem, filename string) error {
+	data, err := xml.MarshalIndent(xmlExport{Items: items}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file, err := createOutput(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Abort()
+
+	if _, err := io.WriteString(file, xml.Header); err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return file.Close()
+}